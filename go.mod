@@ -2,4 +2,4 @@ module tabular_method
 
 go 1.18
 
-require golang.org/x/exp v0.0.0-20221106115401-f9659909a136 // indirect
+require golang.org/x/exp v0.0.0-20221106115401-f9659909a136
@@ -1,64 +1,433 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"tabular_method/quinemccluskey"
 )
 
+// term is a minterm or don't care, given in JSON either as an integer or, for
+// functions with more than 64 inputs, as a string of '1's and '0's.
+type term string
+
+func (t *term) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*t = term(s)
+		return nil
+	}
+
+	var v uint64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*t = term(strconv.FormatUint(v, 2))
+	return nil
+}
+
+// logicFunctionOutput is an output given either by lists of its minterms and
+// don't cares, or of its minterms and off-set, or by Boolean expressions of
+// its inputs.
 type logicFunctionOutput struct {
-	S []uint64 `json:"s"`
-	D []uint64 `json:"d"`
+	S     []term `json:"s"`
+	D     []term `json:"d"`
+	R     []term `json:"r"`
+	Expr  string `json:"expr"`
+	DExpr string `json:"dexpr"`
 }
 
+func termStrings(terms []term) []string {
+	s := []string{}
+	for _, t := range terms {
+		s = append(s, string(t))
+	}
+	return s
+}
+
+// objectKeys returns the keys of the JSON object held in data, in the order
+// they appear.
+func objectKeys(data []byte) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key.(string))
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// check prints e as a diagnostic and exits with a non-zero status if it is
+// not nil.
 func check(e error) {
 	if e != nil {
-		panic(e)
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], e)
+		os.Exit(1)
 	}
 }
 
-func main() {
-	functionFilePath := os.Args[1]
-	jsonStream, err := os.ReadFile(functionFilePath)
-	check(err)
-
+// addJSON adds the outputs of a function given as a JSON object to the
+// LogicFunction, in the order they appear, returning the labels of its inputs
+// and outputs.
+func addJSON(logicFunction *quinemccluskey.LogicFunction, data []byte) (quinemccluskey.InputLabels, quinemccluskey.OutputLabels, error) {
 	var inLabels quinemccluskey.InputLabels
 	var outLabels quinemccluskey.OutputLabels
 
 	var topLevel map[string]json.RawMessage
-	e := json.Unmarshal(jsonStream, &topLevel)
-	check(e)
+	e := json.Unmarshal(data, &topLevel)
+	if e != nil {
+		return inLabels, outLabels, e
+	}
+
+	// outputs are added in the order they appear in the file
+	keys, e := objectKeys(data)
+	if e != nil {
+		return inLabels, outLabels, e
+	}
 
+	labelled := 0
 	if inputLabelBlob, ok := topLevel["inputs"]; ok {
 		var inputLabels map[int]string
 		e := json.Unmarshal(inputLabelBlob, &inputLabels)
-		check(e)
+		if e != nil {
+			return inLabels, outLabels, fmt.Errorf("inputs: %w", e)
+		}
 
 		for label := range inputLabels {
 			inLabels.Set(label, inputLabels[label])
+			if label >= labelled {
+				labelled = label + 1
+			}
+		}
+	}
+
+	numInputs := 0
+	if numInputsBlob, ok := topLevel["n"]; ok {
+		e := json.Unmarshal(numInputsBlob, &numInputs)
+		if e != nil {
+			return inLabels, outLabels, fmt.Errorf("n: %w", e)
 		}
 	}
 
 	outputs := []logicFunctionOutput{}
-	for item := range topLevel {
-		if item == "inputs" {
+	for _, item := range keys {
+		if item == "inputs" || item == "n" {
 			continue
 		}
 
 		outputs = append(outputs, logicFunctionOutput{})
 		e := json.Unmarshal(topLevel[item], &outputs[len(outputs)-1])
-		check(e)
+		if e != nil {
+			return inLabels, outLabels, fmt.Errorf("output %q: %w", item, e)
+		}
 		outLabels.Add(item)
 	}
 
+	// expressions are parsed once every input label is known
+	expressions := make([][2]quinemccluskey.Expression, len(outputs))
+	width, hasExpressions := 0, false
+	for i, output := range outputs {
+		if output.Expr == "" && output.DExpr == "" {
+			for _, t := range append(append(append([]term{}, output.S...), output.D...), output.R...) {
+				if len(t) > width {
+					width = len(t)
+				}
+			}
+			continue
+		}
+
+		if len(output.S) > 0 || len(output.D) > 0 || output.R != nil {
+			return inLabels, outLabels, fmt.Errorf("output %q: terms cannot be given with an expression", outLabels.Str(i))
+		}
+		hasExpressions = true
+
+		for j, text := range []string{output.Expr, output.DExpr} {
+			if text == "" {
+				continue
+			}
+			expression, e := quinemccluskey.ParseExpression(text, inLabels)
+			if e != nil {
+				return inLabels, outLabels, fmt.Errorf("output %q: %w", outLabels.Str(i), e)
+			}
+			expressions[i][j] = expression
+			if expression.NumInputs() > width {
+				width = expression.NumInputs()
+			}
+		}
+	}
+
+	// expressions are evaluated over every input used by the function or
+	// given a label, so a constant expression may have labelled inputs
+	if numInputs == 0 && hasExpressions {
+		numInputs = width
+		if labelled > numInputs {
+			numInputs = labelled
+		}
+	}
+
+	e = logicFunction.SetNumInputs(numInputs)
+	if e != nil {
+		return inLabels, outLabels, fmt.Errorf("n: %w", e)
+	}
+
+	for i, output := range outputs {
+		if (output.Expr != "" || output.DExpr != "") && numInputs == 0 {
+			e = addConstant(logicFunction, expressions[i][0], expressions[i][1])
+		} else if output.Expr != "" || output.DExpr != "" {
+			e = logicFunction.AddOutputExpression(expressions[i][0], expressions[i][1])
+		} else if output.R != nil && len(output.D) > 0 {
+			e = fmt.Errorf("don't cares cannot be given with an off-set")
+		} else if output.R != nil {
+			e = logicFunction.AddOutputOffSetTerms(termStrings(output.S), termStrings(output.R))
+		} else {
+			e = logicFunction.AddOutputTerms(termStrings(output.S), termStrings(output.D))
+		}
+		if e != nil {
+			return inLabels, outLabels, fmt.Errorf("output %q: %w", outLabels.Str(i), e)
+		}
+	}
+
+	return inLabels, outLabels, nil
+}
+
+// addConstant adds an output given by expressions of no inputs to the
+// LogicFunction, which AddOutputExpression cannot add as the number of inputs
+// is not declared. As for AddOutputExpression, the output is a don't care if
+// both expressions are 1.
+func addConstant(logicFunction *quinemccluskey.LogicFunction, expression quinemccluskey.Expression, dontCares quinemccluskey.Expression) error {
+	minterms, e := expression.Minterms(0)
+	if e != nil {
+		return e
+	}
+	dontCareTerms, e := dontCares.Minterms(0)
+	if e != nil {
+		return e
+	}
+	if len(dontCareTerms) > 0 {
+		minterms = nil
+	}
+
+	return logicFunction.AddOutputTerms(minterms, dontCareTerms)
+}
+
+func main() {
+	all := flag.Bool("all", false, "print every minimum cost cover")
+	limit := flag.Int("limit", 0, "maximum number of covers printed by -all, or 0 for no limit")
+	timeout := flag.Duration("timeout", 0, "give up if no cover is found within `duration`, or 0 for no limit")
+	trace := flag.String("trace", "", "write a newline-delimited JSON trace of each step to `file` instead of printing tables")
+	pla := flag.Bool("pla", false, "print covers in the Berkeley PLA format instead of as equations")
+	verilog := flag.Bool("verilog", false, "print covers as Verilog modules instead of as equations")
+	vhdl := flag.Bool("vhdl", false, "print covers as VHDL entities instead of as equations")
+	testbench := flag.String("testbench", "", "with -vhdl, also write a self-checking VHDL testbench of each entity to `file`")
+	goSource := flag.Bool("go", false, "print the minimum cost cover as Go source instead of as equations")
+	goTest := flag.String("gotest", "", "with -go, also write a Go test of the source to `file`")
+	goPackage := flag.String("package", "", "`name` of the package of the Go source printed by -go")
+	combined := flag.Bool("combined", false, "with -go, compute every output in a single function")
+	module := flag.String("module", "", "`name` of the Verilog module, VHDL entity or combined Go function printed by -verilog, -vhdl or -go")
+	buses := flag.Bool("buses", false, "group inputs and outputs labelled as bits of a bus, such as addr[0], into vector ports")
+	format := flag.String("format", "", "read the function as `format` json, pla, sigma, csv, tsv or rom, instead of choosing by the file extension")
+	inputs := flag.Int("inputs", 0, "number of input columns of a truth table, or 0 if an empty column separates them from the outputs")
+	addressBits := flag.Int("addr", 0, "number of address bits of a ROM")
+	dataBits := flag.Int("data", 0, "number of data bits of a ROM")
+	flag.Parse()
+
+	if *all && *goSource {
+		check(fmt.Errorf("-go prints a single cover, and cannot be used with -all"))
+	}
+
+	if flag.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] <function.json | .pla | .txt | .csv | .tsv | .hex | ->\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	// the function is read from standard input if the path is "-"
+	functionFilePath := flag.Arg(0)
+	var data []byte
+	var err error
+	if functionFilePath == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(functionFilePath)
+	}
+	check(err)
+
+	// sigma or pi notation is given in text files, ROM contents in hex files,
+	// and anything else is JSON
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(functionFilePath)) {
+		case ".pla":
+			*format = "pla"
+		case ".txt":
+			*format = "sigma"
+		case ".csv":
+			*format = "csv"
+		case ".tsv":
+			*format = "tsv"
+		case ".hex", ".mem", ".rom":
+			*format = "rom"
+		default:
+			*format = "json"
+		}
+	}
+
 	var logicFunction quinemccluskey.LogicFunction
-	logicFunction.Init(true)
+	logicFunction.Init(*trace == "" && !*pla && !*verilog && !*vhdl && !*goSource)
 
-	for _, output := range outputs {
-		logicFunction.AddOutput(output.S, output.D)
+	// the trace is complete once the covers have been printed
+	if *trace != "" {
+		traceFile, err := os.Create(*trace)
+		check(err)
+		observer := &quinemccluskey.JSONObserver{Writer: traceFile}
+		defer func() {
+			check(observer.Err)
+			check(traceFile.Close())
+		}()
+		logicFunction.SetObserver(observer)
 	}
 
-	fmt.Printf(logicFunction.GetMinimumCostCover(inLabels, outLabels))
+	var inLabels quinemccluskey.InputLabels
+	var outLabels quinemccluskey.OutputLabels
+	var spec quinemccluskey.Specification
+	var e error
+
+	switch *format {
+	case "json":
+		inLabels, outLabels, e = addJSON(&logicFunction, data)
+	case "pla":
+		spec, e = quinemccluskey.ReadPLA(bytes.NewReader(data))
+	case "sigma":
+		spec, e = quinemccluskey.ParseSigmaPi(string(data))
+	case "csv":
+		spec, e = quinemccluskey.ReadTruthTable(bytes.NewReader(data), ',', *inputs)
+	case "tsv":
+		spec, e = quinemccluskey.ReadTruthTable(bytes.NewReader(data), '\t', *inputs)
+	case "rom":
+		spec, e = quinemccluskey.ReadROM(bytes.NewReader(data), *addressBits, *dataBits)
+	default:
+		check(fmt.Errorf("unknown format %q", *format))
+	}
 
+	if e == nil && *format != "json" {
+		inLabels, outLabels = spec.Labels()
+		e = logicFunction.AddSpecification(spec)
+	}
+	if e != nil {
+		check(fmt.Errorf("%s: %w", functionFilePath, e))
+	}
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	var testbenchFile *os.File
+	if *vhdl && *testbench != "" {
+		testbenchFile, err = os.Create(*testbench)
+		check(err)
+		defer testbenchFile.Close()
+	}
+
+	if !*all {
+		result := logicFunction.GetMinimumCostCoverContext(ctx, inLabels, outLabels)
+		check(result.Err)
+		switch {
+		case *pla:
+			check(quinemccluskey.WritePLA(os.Stdout, result))
+		case *verilog:
+			check(quinemccluskey.WriteVerilog(os.Stdout, result, quinemccluskey.VerilogOptions{ModuleName: *module, Buses: *buses}))
+		case *vhdl:
+			options := quinemccluskey.VHDLOptions{EntityName: *module, Buses: *buses}
+			check(quinemccluskey.WriteVHDL(os.Stdout, result, options))
+			if testbenchFile != nil {
+				check(quinemccluskey.WriteVHDLTestbench(testbenchFile, result, options))
+			}
+		case *goSource:
+			options := quinemccluskey.GoOptions{Package: *goPackage, Combined: *combined, FunctionName: *module}
+			check(quinemccluskey.WriteGo(os.Stdout, result, options))
+			if *goTest != "" {
+				testFile, err := os.Create(*goTest)
+				check(err)
+				defer testFile.Close()
+				check(quinemccluskey.WriteGoTest(testFile, result, options))
+			}
+		default:
+			fmt.Print(result)
+		}
+		return
+	}
+
+	// alternative covers are found by an exact solver
+	logicFunction.SetCoverSolver(quinemccluskey.BranchAndBoundSolver{})
+	covers := logicFunction.GetAllMinimumCostCoversContext(ctx, inLabels, outLabels, *limit)
+	check(covers[0].Err)
+
+	if *pla {
+		fmt.Printf("# %d minimum cost covers\n", len(covers))
+		for i, cover := range covers {
+			fmt.Printf("\n# cover %d\n", i+1)
+			check(quinemccluskey.WritePLA(os.Stdout, cover))
+		}
+		return
+	}
+
+	// each cover is a module or entity of its own, named with the number of
+	// the cover
+	name := *module
+	if name == "" {
+		name = "logic_function"
+	}
+
+	if *verilog {
+		fmt.Printf("// %d minimum cost covers\n", len(covers))
+		for i, cover := range covers {
+			fmt.Printf("\n// cover %d\n", i+1)
+			check(quinemccluskey.WriteVerilog(os.Stdout, cover, quinemccluskey.VerilogOptions{ModuleName: fmt.Sprintf("%s_%d", name, i+1), Buses: *buses}))
+		}
+		return
+	}
+
+	if *vhdl {
+		fmt.Printf("-- %d minimum cost covers\n", len(covers))
+		for i, cover := range covers {
+			options := quinemccluskey.VHDLOptions{EntityName: fmt.Sprintf("%s_%d", name, i+1), Buses: *buses}
+			fmt.Printf("\n-- cover %d\n", i+1)
+			check(quinemccluskey.WriteVHDL(os.Stdout, cover, options))
+			if testbenchFile != nil {
+				if i > 0 {
+					_, err = fmt.Fprintf(testbenchFile, "\n")
+					check(err)
+				}
+				check(quinemccluskey.WriteVHDLTestbench(testbenchFile, cover, options))
+			}
+		}
+		return
+	}
+
+	fmt.Printf("%d minimum cost covers\n", len(covers))
+	for i, cover := range covers {
+		fmt.Printf("\ncover %d:\n%s", i+1, cover)
+	}
 }
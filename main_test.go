@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"tabular_method/quinemccluskey"
+)
+
+func TestAddJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"terms", `{"y": {"s": [1, 2, 3]}}`, "y = x1 + x0\n"},
+		{"expression", `{"inputs": {"0": "b", "1": "a"}, "y": {"expr": "a + b"}}`, "y = a + b\n"},
+		{"labelled constant", `{"inputs": {"0": "a"}, "y": {"expr": "1"}}`, "y = 1\n"},
+		{"constants", `{"one": {"expr": "1"}, "zero": {"expr": "0"}, "dc": {"expr": "1", "dexpr": "1"}}`, "one = 1\nzero = 0\ndc = 0\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var f quinemccluskey.LogicFunction
+			f.Init(false)
+			inLabels, outLabels, err := addJSON(&f, []byte(test.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := f.GetMinimumCostCover(inLabels, outLabels)
+			if r.Err != nil {
+				t.Fatal(r.Err)
+			}
+			if got := r.String(); got != test.want {
+				t.Errorf("the function added is\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package quinemccluskey
+
+// CostModel assigns a cost to each implicant of a cover. The cost of a cover
+// is the sum of the costs of its implicants, which allows exact CoverSolvers
+// to bound the cost of partial covers.
+type CostModel interface {
+	// ImplicantCost returns the cost of including im in a cover, where im
+	// implements a product for each of its outputs.
+	ImplicantCost(im Implicant) float64
+}
+
+// ProductTermCost counts the number of distinct product terms in a cover,
+// such as the rows of a PLA. A product shared by several outputs is counted
+// once.
+type ProductTermCost struct{}
+
+// ImplicantCost implements CostModel.
+func (ProductTermCost) ImplicantCost(im Implicant) float64 {
+	return 1
+}
+
+// LiteralCost counts the number of literals across the product terms of a
+// cover. A product shared by several outputs is counted once.
+type LiteralCost struct{}
+
+// ImplicantCost implements CostModel.
+func (LiteralCost) ImplicantCost(im Implicant) float64 {
+	return float64(im.LiteralCount())
+}
+
+// andGateInputs returns the number of AND gate inputs needed to implement im,
+// which is 0 for a product of a single literal or no literals.
+func andGateInputs(im Implicant) int {
+	if im.LiteralCount() < 2 {
+		return 0
+	}
+	return im.LiteralCount()
+}
+
+// GateInputCost counts the number of gate inputs needed to implement a cover
+// as a two-level circuit, being the inputs of each AND gate plus one OR gate
+// input for each product of each output. Each output is treated as a separate
+// circuit, so a product shared by several outputs is paid for by each output.
+type GateInputCost struct{}
+
+// ImplicantCost implements CostModel.
+func (GateInputCost) ImplicantCost(im Implicant) float64 {
+	return float64(len(im.Outputs()) * (andGateInputs(im) + 1))
+}
+
+// SharedGateInputCost counts the number of gate inputs needed to implement a
+// cover as a multi-output two-level circuit, in which a product shared by
+// several outputs is implemented by a single AND gate. Each product costs the
+// inputs of its AND gate once, plus one OR gate input for each of its outputs.
+type SharedGateInputCost struct{}
+
+// ImplicantCost implements CostModel.
+func (SharedGateInputCost) ImplicantCost(im Implicant) float64 {
+	return float64(andGateInputs(im) + len(im.Outputs()))
+}
+
+// coverCostFunction returns a cost function for a CoverProblem over the passed
+// primes using model. A fraction of the number of literals of each prime is
+// added, too small to outweigh any difference in the total cost of two covers
+// under a model with whole number costs, so that ties are broken in favour of
+// the cover with the fewest literals.
+func coverCostFunction(primes []Implicant, model CostModel, width int) func(int) float64 {
+	literalWeight := 1 / float64(width*len(primes)+1)
+	return func(p int) float64 {
+		return model.ImplicantCost(primes[p]) + literalWeight*float64(primes[p].LiteralCount())
+	}
+}
+
+// totalCost returns the sum of the costs of the passed implicants under model.
+func totalCost(cover []implicant, model CostModel, width int) float64 {
+	cost := 0.0
+	for _, im := range cover {
+		cost += model.ImplicantCost(Implicant{im, width})
+	}
+	return cost
+}
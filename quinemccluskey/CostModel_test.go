@@ -0,0 +1,68 @@
+package quinemccluskey
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestImplicantCost(t *testing.T) {
+	tests := []struct {
+		cube    string
+		outputs uint64
+		// want holds the cost under ProductTermCost, LiteralCost,
+		// GateInputCost and SharedGateInputCost
+		want [4]float64
+	}{
+		{"xxx", 0b1, [4]float64{1, 0, 1, 1}},
+		{"1xx", 0b1, [4]float64{1, 1, 1, 1}},
+		{"10x", 0b1, [4]float64{1, 2, 3, 3}},
+		{"10x", 0b11, [4]float64{1, 2, 6, 4}},
+		{"101", 0b111, [4]float64{1, 3, 12, 6}},
+	}
+
+	models := []CostModel{ProductTermCost{}, LiteralCost{}, GateInputCost{}, SharedGateInputCost{}}
+	for _, test := range tests {
+		c, err := ParseCube(test.cube)
+		if err != nil {
+			t.Fatal(err)
+		}
+		im := Implicant{implicant{c.im.literals, c.im.xMask, newBitset(test.outputs), false}, c.width}
+
+		for i, model := range models {
+			if got := model.ImplicantCost(im); got != test.want[i] {
+				t.Errorf("%T.ImplicantCost(%s with outputs %v) = %v, want %v", model, test.cube, im.Outputs(), got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestCoverProblemCost(t *testing.T) {
+	// the prime x1 of both outputs covers no minterm of output 0, so is only
+	// costed for output 1
+	var table coverTable
+	minterms := [][]bitset{{newBitset(0b10)}, {newBitset(0b01), newBitset(0b11)}}
+	primes := []implicant{
+		{newBitset(0b10), newBitset(0b01), newBitset(0b11), false},
+		{newBitset(0b01), newBitset(0b10), newBitset(0b11), false},
+	}
+	table.build(context.Background(), minterms, primes)
+	problem := table.problem(GateInputCost{}, 2)
+
+	tests := []struct {
+		outputs []int
+		cost    int
+	}{
+		{[]int{0, 1}, 2},
+		{[]int{1}, 1},
+	}
+	for p, test := range tests {
+		if outputs := problem.Primes[p].Outputs(); !slices.Equal(outputs, test.outputs) {
+			t.Errorf("prime %s applies to outputs %v, want %v", problem.Primes[p], outputs, test.outputs)
+		}
+		if cost := int(problem.Cost(p)); cost != test.cost {
+			t.Errorf("prime %s costs %d, want %d", problem.Primes[p], cost, test.cost)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package quinemccluskey
+
+import (
+	"context"
+	"math"
+)
+
+// CoverRow is a minterm of a single output which must be covered. Minterm
+// holds a '1' or '0' for each input, with the most significant input first.
+type CoverRow struct {
+	Output  int
+	Minterm string
+}
+
+// CoverProblem describes the part of a cover table remaining once essential
+// primes have been removed.
+type CoverProblem struct {
+	// Primes lists the prime implicants which may be selected, each applying
+	// to the outputs of the rows it covers.
+	Primes []Implicant
+	// Rows lists the minterms which are still to be covered.
+	Rows []CoverRow
+	// Covers lists, for each prime, the indices of the rows that it covers.
+	Covers [][]int
+	// Cost returns the cost of selecting the prime at the passed index. The
+	// cost of a cover is the sum of the costs of its primes.
+	Cost func(prime int) float64
+	// Context is done when the solve has been cancelled, in which case the
+	// CoverSolver should return promptly. The selection returned is then
+	// discarded. A nil Context is never done.
+	Context context.Context
+}
+
+// cancelled tests whether the CoverProblem's Context is done.
+func (problem CoverProblem) cancelled() bool {
+	return problem.Context != nil && problem.Context.Err() != nil
+}
+
+// CoverSolver selects a set of primes from a CoverProblem which together
+// cover every row.
+type CoverSolver interface {
+	// SolveCover returns the indices of the selected primes, and whether the
+	// selection is proven to be of minimum cost. If a row is covered by no
+	// prime, the selection cannot cover every row and false is returned.
+	SolveCover(problem CoverProblem) ([]int, bool)
+}
+
+// CoverEnumerator is implemented by CoverSolvers which can list every minimum
+// cost selection of primes for a CoverProblem, rather than just one of them.
+type CoverEnumerator interface {
+	CoverSolver
+	// EnumerateCovers returns the indices of the selected primes of each
+	// minimum cost selection, up to limit selections, or every selection if
+	// limit is 0. The returned boolean reports whether the selections are
+	// proven to be of minimum cost.
+	EnumerateCovers(problem CoverProblem, limit int) ([][]int, bool)
+}
+
+// costTolerance is the relative difference below which two costs are
+// considered equal, absorbing rounding in the sums of prime costs.
+const costTolerance = 1e-9
+
+// costsEqual tests whether two costs are equal to within costTolerance.
+func costsEqual(a float64, b float64) bool {
+	return math.Abs(a-b) <= costTolerance*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+// GreedySolver repeatedly selects the prime which covers the most remaining
+// rows per unit of cost, breaking ties in favour of the cheapest prime. It is
+// fast, but is not guaranteed to find a minimum cost cover.
+type GreedySolver struct{}
+
+// SolveCover implements CoverSolver.
+func (GreedySolver) SolveCover(problem CoverProblem) ([]int, bool) {
+	selected := []int{}
+
+	remaining := newIndexSet(len(problem.Rows))
+	for r := range problem.Rows {
+		remaining.add(r)
+	}
+
+	// select primes iteratively until all rows are covered
+	for remaining.count() > 0 {
+		if problem.cancelled() {
+			return selected, false
+		}
+
+		// pL is the set of primes which cover the greatest number of
+		// remaining rows per unit of cost
+		pLIndices := []int{}
+		bestRatio := 0.0
+		for p, cover := range problem.Covers {
+			totalRowsCovered := 0
+			for _, r := range cover {
+				if remaining.has(r) {
+					totalRowsCovered++
+				}
+			}
+			if totalRowsCovered == 0 {
+				continue
+			}
+
+			ratio := float64(totalRowsCovered) / problem.Cost(p)
+			if len(pLIndices) == 0 || ratio > bestRatio {
+				bestRatio = ratio
+				pLIndices = []int{p}
+			} else if ratio == bestRatio {
+				pLIndices = append(pLIndices, p)
+			}
+		}
+
+		// a remaining row which no prime covers cannot be covered
+		if len(pLIndices) == 0 {
+			return selected, false
+		}
+
+		// pI is the first occurance from the set of primes with the lowest
+		// cost in pL
+		pI := pLIndices[0]
+		for _, index := range pLIndices[1:] {
+			if problem.Cost(index) < problem.Cost(pI) {
+				pI = index
+			}
+		}
+
+		selected = append(selected, pI)
+		for _, r := range problem.Covers[pI] {
+			remaining.remove(r)
+		}
+	}
+
+	return selected, false
+}
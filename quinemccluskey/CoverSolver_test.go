@@ -0,0 +1,137 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// testOutput holds the minterms and don't cares of an output of a function
+// under test.
+type testOutput struct {
+	minterms  []uint64
+	dontCares []uint64
+}
+
+// randomOutputs returns n outputs of a function of the passed number of
+// inputs, each term of which is a minterm, a don't care or neither at random.
+func randomOutputs(rng *rand.Rand, inputs int, n int) []testOutput {
+	outputs := make([]testOutput, n)
+	for o := range outputs {
+		for term := uint64(0); term < 1<<inputs; term++ {
+			switch rng.Intn(5) {
+			case 0, 1:
+				outputs[o].minterms = append(outputs[o].minterms, term)
+			case 2:
+				outputs[o].dontCares = append(outputs[o].dontCares, term)
+			}
+		}
+	}
+	return outputs
+}
+
+// newTestFunction returns a LogicFunction of the passed number of inputs with
+// the passed outputs added.
+func newTestFunction(t *testing.T, inputs int, outputs []testOutput) *LogicFunction {
+	t.Helper()
+
+	var f LogicFunction
+	f.Init(false)
+	if err := f.SetNumInputs(inputs); err != nil {
+		t.Fatalf("SetNumInputs(%d) = %v", inputs, err)
+	}
+	for _, output := range outputs {
+		if err := f.AddOutput(output.minterms, output.dontCares); err != nil {
+			t.Fatalf("AddOutput(%v, %v) = %v", output.minterms, output.dontCares, err)
+		}
+	}
+	return &f
+}
+
+// checkResult fails the test unless the cover of each output held by r is 1
+// for each of its minterms and 0 for each term which is not a don't care.
+func checkResult(t *testing.T, r Result, outputs []testOutput) {
+	t.Helper()
+
+	if r.Err != nil {
+		t.Fatalf("Result.Err = %v", r.Err)
+	}
+	if len(r.Outputs) != len(outputs) {
+		t.Fatalf("Result has %d outputs, want %d", len(r.Outputs), len(outputs))
+	}
+
+	for o, output := range outputs {
+		want := map[uint64]byte{}
+		for _, term := range output.dontCares {
+			want[term] = '-'
+		}
+		for _, term := range output.minterms {
+			want[term] = '1'
+		}
+
+		for term := uint64(0); term < 1<<r.NumInputs; term++ {
+			// a product of sums covers the terms for which it is 0
+			value := r.Outputs[o].Form == ProductOfSums
+			for _, im := range r.Outputs[o].Implicants {
+				if im.Cube().Covers(fmt.Sprintf("%0*b", r.NumInputs, term)) {
+					value = !value
+					break
+				}
+			}
+
+			if w, ok := want[term]; (w == '1') != value && w != '-' || !ok && value {
+				t.Errorf("output %d of term %d is %v, want %c\n%s", o, term, value, w, r)
+			}
+		}
+	}
+}
+
+func TestCoverSolversAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	models := []CostModel{ProductTermCost{}, LiteralCost{}, GateInputCost{}, SharedGateInputCost{}}
+
+	for i := 0; i < 40; i++ {
+		inputs := 2 + rng.Intn(3)
+		outputs := randomOutputs(rng, inputs, 1+rng.Intn(3))
+		model := models[i%len(models)]
+
+		t.Run(fmt.Sprintf("%d inputs %d outputs %T", inputs, len(outputs), model), func(t *testing.T) {
+			costs := []float64{}
+			for _, solver := range []CoverSolver{PetrickSolver{}, BranchAndBoundSolver{}, GreedySolver{}} {
+				f := newTestFunction(t, inputs, outputs)
+				f.SetCoverSolver(solver)
+				f.SetCostModel(model)
+				r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+				checkResult(t, r, outputs)
+				costs = append(costs, r.Cost)
+
+				if _, exact := solver.(CoverEnumerator); exact && !r.Optimal {
+					t.Errorf("%T cover is not optimal", solver)
+				}
+			}
+
+			if !costsEqual(costs[0], costs[1]) || costs[2] < costs[0] && !costsEqual(costs[0], costs[2]) {
+				t.Errorf("cover costs are Petrick %v, branch-and-bound %v, greedy %v, want Petrick and branch-and-bound equal, and no greater than greedy", costs[0], costs[1], costs[2])
+			}
+		})
+	}
+}
+
+func TestCoverSolversInfeasible(t *testing.T) {
+	// the second row is covered by no prime
+	problem := newTestProblem(2, []float64{1}, [][]int{{0}})
+	empty := newTestProblem(1, []float64{}, [][]int{})
+
+	for _, solver := range []CoverSolver{PetrickSolver{}, BranchAndBoundSolver{}, GreedySolver{}} {
+		for _, p := range []CoverProblem{problem, empty} {
+			if selected, optimal := solver.SolveCover(p); optimal {
+				t.Errorf("%T SolveCover() of an infeasible problem = %v, optimal, want not optimal", solver, selected)
+			}
+			if enumerator, ok := solver.(CoverEnumerator); ok {
+				if covers, optimal := enumerator.EnumerateCovers(p, 0); optimal {
+					t.Errorf("%T EnumerateCovers() of an infeasible problem = %v, optimal, want not optimal", solver, covers)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package quinemccluskey
+
+import (
+	"fmt"
+)
+
+// Cube is a product of the inputs of a function, in which each input is a
+// '1' or '0' literal, or an 'x' if it does not appear in the product. Cubes
+// taking part in an operation are expected to have the same number of inputs.
+type Cube struct {
+	im    implicant
+	width int
+}
+
+// ParseCube returns the Cube represented by a string with a '1', '0' or 'x'
+// for each input, with the most significant input first, as produced by
+// Cube.String. A '-' is also accepted in place of an 'x'. If s contains any
+// other character, ErrInvalidTerm is returned.
+func ParseCube(s string) (Cube, error) {
+	c := Cube{width: len(s)}
+	for i := 0; i < len(s); i++ {
+		bit := len(s) - 1 - i
+		switch s[i] {
+		case '1':
+			c.im.literals = c.im.literals.with(bit)
+		case '0':
+		case 'x', 'X', '-':
+			c.im.xMask = c.im.xMask.with(bit)
+		default:
+			return Cube{}, fmt.Errorf("%w: invalid character %q in cube %q", ErrInvalidTerm, s[i], s)
+		}
+	}
+
+	return c, nil
+}
+
+// Cube returns the product of the implicant's inputs as a Cube.
+func (im Implicant) Cube() Cube {
+	return Cube{implicant{im.im.literals, im.im.xMask, bitset{}, false}, im.width}
+}
+
+// newCube returns a Cube with the inputs of the passed implicant, and the
+// greater of the widths of the passed cubes.
+func newCube(im implicant, c Cube, d Cube) Cube {
+	width := c.width
+	if d.width > width {
+		width = d.width
+	}
+	return Cube{implicant{im.literals, im.xMask, bitset{}, false}, width}
+}
+
+// String returns a representation of the cube using '1's, '0's, and 'x's for
+// each input, with the most significant input first.
+func (c Cube) String() string {
+	return c.im.stringify(c.width)
+}
+
+// Width returns the number of inputs of the cube.
+func (c Cube) Width() int {
+	return c.width
+}
+
+// LiteralCount returns the number of inputs which appear in the cube as a
+// literal.
+func (c Cube) LiteralCount() int {
+	return c.width - c.im.xMask.count()
+}
+
+// Equals tests whether two cubes contain the same minterms. Cubes of different
+// numbers of inputs are never equal.
+func (c Cube) Equals(d Cube) bool {
+	return c.width == d.width && c.im.equals(d.im)
+}
+
+// Covers tests whether the cube contains the passed minterm, given as a string
+// of '1's and '0's with the most significant input first. A minterm of a
+// different number of inputs is never covered.
+func (c Cube) Covers(minterm string) bool {
+	term, ok := parseBinary(minterm)
+	return ok && len(minterm) == c.width && c.im.covers(term)
+}
+
+// Contains tests whether every minterm of d is also a minterm of the calling
+// cube. A cube of a different number of inputs is never contained.
+func (c Cube) Contains(d Cube) bool {
+	return c.width == d.width && c.im.contains(d.im)
+}
+
+// Distance returns the number of inputs which appear as a literal of opposite
+// polarity in the two cubes. Cubes intersect only if their distance is 0. The
+// cubes must have the same number of inputs, as an input missing from one
+// cube is treated as an 'x'.
+func (c Cube) Distance(d Cube) int {
+	return c.im.literals.xor(d.im.literals).andNot(c.im.xMask.or(d.im.xMask)).count()
+}
+
+// Intersection returns the cube of the minterms contained in both cubes, and
+// whether the intersection is non-empty. Cubes of different numbers of inputs
+// have no minterms in common.
+func (c Cube) Intersection(d Cube) (Cube, bool) {
+	if c.width != d.width || !c.im.intersects(d.im) {
+		return Cube{}, false
+	}
+	return newCube(c.im.intersection(d.im), c, d), true
+}
+
+// Supercube returns the smallest cube containing the minterms of both cubes.
+func (c Cube) Supercube(d Cube) Cube {
+	return newCube(c.im.supercube(d.im), c, d)
+}
+
+// Consensus returns the consensus of the two cubes, and whether it exists. For
+// cubes at a distance of 1, the consensus is the cube of minterms adjacent to
+// both cubes, found by raising the conflicting input of their intersection.
+// For intersecting cubes it is their intersection, and for cubes at a greater
+// distance it does not exist.
+func (c Cube) Consensus(d Cube) (Cube, bool) {
+	conflict := c.im.literals.xor(d.im.literals).andNot(c.im.xMask.or(d.im.xMask))
+	if conflict.count() > 1 {
+		return Cube{}, false
+	}
+
+	xMask := c.im.xMask.and(d.im.xMask).or(conflict)
+	im := implicant{c.im.literals.or(d.im.literals).andNot(xMask), xMask, bitset{}, false}
+	return newCube(im, c, d), true
+}
+
+// Cofactor returns the cofactor of the calling cube with respect to d, in
+// which every input that is a literal in d becomes an 'x', and whether it is
+// non-empty. The cofactor is empty if the cubes do not intersect.
+func (c Cube) Cofactor(d Cube) (Cube, bool) {
+	if !c.im.intersects(d.im) {
+		return Cube{}, false
+	}
+	return newCube(c.im.cofactor(d.im, lowBits(c.width)), c, d), true
+}
+
+// Sharp returns a list of cubes which together contain exactly the minterms of
+// the calling cube that are not minterms of d. For each input which is an 'x'
+// in the calling cube and a literal in d, the list holds the calling cube with
+// that input set to the opposite literal, so the cubes may overlap. The list
+// is empty if d contains the calling cube.
+func (c Cube) Sharp(d Cube) []Cube {
+	if !c.im.intersects(d.im) {
+		return []Cube{c}
+	}
+
+	result := []Cube{}
+	raised := c.im.xMask.andNot(d.im.xMask)
+	for i := 0; i < raised.msbPos(); i++ {
+		if !raised.bit(i) {
+			continue
+		}
+
+		im := implicant{c.im.literals, c.im.xMask.without(i), bitset{}, false}
+		if !d.im.literals.bit(i) {
+			im.literals = im.literals.with(i)
+		}
+		result = append(result, newCube(im, c, d))
+	}
+
+	return result
+}
@@ -0,0 +1,212 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// mustParseCube returns the Cube represented by s, failing the test if it is
+// invalid.
+func mustParseCube(t *testing.T, s string) Cube {
+	t.Helper()
+
+	c, err := ParseCube(s)
+	if err != nil {
+		t.Fatalf("ParseCube(%q) = %v", s, err)
+	}
+	return c
+}
+
+// cubeMinterms returns the minterms of a cube as strings.
+func cubeMinterms(c Cube) map[string]bool {
+	minterms := map[string]bool{}
+	for term := 0; term < 1<<c.Width(); term++ {
+		if s := fmt.Sprintf("%0*b", c.Width(), term); c.Covers(s) {
+			minterms[s] = true
+		}
+	}
+	return minterms
+}
+
+func TestParseCube(t *testing.T) {
+	tests := []struct {
+		s        string
+		want     string
+		literals int
+		err      error
+	}{
+		{"", "", 0, nil},
+		{"10x", "10x", 2, nil},
+		{"1-0X", "1x0x", 2, nil},
+		{"xxxx", "xxxx", 0, nil},
+		{"102", "", 0, ErrInvalidTerm},
+		{"1 0", "", 0, ErrInvalidTerm},
+	}
+
+	for _, test := range tests {
+		c, err := ParseCube(test.s)
+		if !errors.Is(err, test.err) {
+			t.Errorf("ParseCube(%q) error = %v, want %v", test.s, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if c.String() != test.want || c.Width() != len(test.s) || c.LiteralCount() != test.literals {
+			t.Errorf("ParseCube(%q) = %s of width %d with %d literals, want %s of width %d with %d literals", test.s, c, c.Width(), c.LiteralCount(), test.want, len(test.s), test.literals)
+		}
+	}
+}
+
+func TestCubeCovers(t *testing.T) {
+	tests := []struct {
+		cube    string
+		minterm string
+		want    bool
+	}{
+		{"10x", "100", true},
+		{"10x", "101", true},
+		{"10x", "111", false},
+		{"xxx", "010", true},
+		{"10x", "1x0", false},
+		{"10x", "102", false},
+		{"10x", "10", false},
+		{"10x", "0100", false},
+	}
+
+	for _, test := range tests {
+		if got := mustParseCube(t, test.cube).Covers(test.minterm); got != test.want {
+			t.Errorf("%s.Covers(%q) = %v, want %v", test.cube, test.minterm, got, test.want)
+		}
+	}
+}
+
+func TestCubeOperations(t *testing.T) {
+	tests := []struct {
+		c, d         string
+		equals       bool
+		contains     bool
+		distance     int
+		intersection string
+		supercube    string
+		consensus    string
+		cofactor     string
+	}{
+		{"10x", "10x", true, true, 0, "10x", "10x", "10x", "xxx"},
+		{"1xx", "10x", false, true, 0, "10x", "1xx", "10x", "xxx"},
+		{"10x", "1xx", false, false, 0, "10x", "1xx", "10x", "x0x"},
+		{"10x", "x01", false, false, 0, "101", "x0x", "101", "1xx"},
+		{"10x", "11x", false, false, 1, "", "1xx", "1xx", ""},
+		{"10x", "0x1", false, false, 1, "", "xxx", "x01", ""},
+		{"100", "011", false, false, 3, "", "xxx", "", ""},
+	}
+
+	for _, test := range tests {
+		c, d := mustParseCube(t, test.c), mustParseCube(t, test.d)
+		name := test.c + " and " + test.d
+
+		if got := c.Equals(d); got != test.equals {
+			t.Errorf("%s: Equals() = %v, want %v", name, got, test.equals)
+		}
+		if got := c.Contains(d); got != test.contains {
+			t.Errorf("%s: Contains() = %v, want %v", name, got, test.contains)
+		}
+		if got := c.Distance(d); got != test.distance {
+			t.Errorf("%s: Distance() = %v, want %v", name, got, test.distance)
+		}
+		if got := c.Supercube(d).String(); got != test.supercube {
+			t.Errorf("%s: Supercube() = %s, want %s", name, got, test.supercube)
+		}
+
+		for _, op := range []struct {
+			name string
+			f    func(Cube) (Cube, bool)
+			want string
+		}{
+			{"Intersection", c.Intersection, test.intersection},
+			{"Consensus", c.Consensus, test.consensus},
+			{"Cofactor", c.Cofactor, test.cofactor},
+		} {
+			got, ok := op.f(d)
+			if ok != (op.want != "") || ok && got.String() != op.want {
+				t.Errorf("%s: %s() = %s, %v, want %q", name, op.name, got, ok, op.want)
+			}
+		}
+	}
+}
+
+func TestCubeWidths(t *testing.T) {
+	// the cubes have the same literals, but not the same number of inputs
+	c, d := mustParseCube(t, "0x"), mustParseCube(t, "00x")
+
+	if c.Equals(d) || d.Equals(c) {
+		t.Errorf("Equals() of %s and %s is true, want false", c, d)
+	}
+	if c.Contains(d) || d.Contains(c) {
+		t.Errorf("Contains() of %s and %s is true, want false", c, d)
+	}
+	if _, ok := c.Intersection(d); ok {
+		t.Errorf("Intersection() of %s and %s is non-empty, want empty", c, d)
+	}
+	if c.Covers("000") || d.Covers("00") {
+		t.Errorf("Covers() of a minterm of a different width is true, want false")
+	}
+}
+
+func TestCubeSharp(t *testing.T) {
+	tests := []struct {
+		c, d string
+		want []string
+	}{
+		{"xxx", "1x0", []string{"xx1", "0xx"}},
+		{"1xx", "1xx", []string{}},
+		{"1xx", "0xx", []string{"1xx"}},
+		{"10x", "xx1", []string{"100"}},
+		{"x1x", "11x", []string{"01x"}},
+	}
+
+	for _, test := range tests {
+		c, d := mustParseCube(t, test.c), mustParseCube(t, test.d)
+
+		got := []string{}
+		covered := map[string]bool{}
+		for _, e := range c.Sharp(d) {
+			got = append(got, e.String())
+			for minterm := range cubeMinterms(e) {
+				covered[minterm] = true
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("%s.Sharp(%s) = %v, want %v", test.c, test.d, got, test.want)
+		}
+
+		// the cubes hold exactly the minterms of c which are not in d
+		for minterm := range cubeMinterms(c) {
+			if covered[minterm] == d.Covers(minterm) {
+				t.Errorf("%s.Sharp(%s) covers %s is %v", test.c, test.d, minterm, covered[minterm])
+			}
+		}
+		for minterm := range covered {
+			if !c.Covers(minterm) {
+				t.Errorf("%s.Sharp(%s) covers %s, which is not in %s", test.c, test.d, minterm, test.c)
+			}
+		}
+	}
+}
+
+func TestImplicantCube(t *testing.T) {
+	f := newTestFunction(t, 3, []testOutput{{[]uint64{4, 5}, nil}})
+	r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+	if r.Err != nil {
+		t.Fatal(r.Err)
+	}
+
+	im := r.Outputs[0].Implicants[0]
+	if c := im.Cube(); !c.Equals(mustParseCube(t, "10x")) || c.Width() != 3 {
+		t.Errorf("Cube() = %s, want 10x", c)
+	}
+	if im.Literals() != "100" || im.DontCareMask() != "001" || im.LiteralCount() != 2 {
+		t.Errorf("Literals() = %s, DontCareMask() = %s, LiteralCount() = %d, want 100, 001, 2", im.Literals(), im.DontCareMask(), im.LiteralCount())
+	}
+}
@@ -0,0 +1,46 @@
+package quinemccluskey
+
+import (
+	"errors"
+)
+
+// Errors returned by LogicFunction, either directly or in Result.Err. Errors
+// may carry further detail, and should be matched using errors.Is.
+var (
+	// ErrInvalidTerm is returned when a minterm, don't care or cube is not a
+	// valid string of '1's and '0's, or of '1's, '0's and 'x's for a cube.
+	ErrInvalidTerm = errors.New("quinemccluskey: invalid term")
+	// ErrConflictingTerms is returned when a term of an output is given as
+	// both a minterm and a don't care.
+	ErrConflictingTerms = errors.New("quinemccluskey: conflicting terms")
+	// ErrInvalidInputCount is returned when the number of inputs declared by
+	// SetNumInputs is negative, or too few for the terms already added.
+	ErrInvalidInputCount = errors.New("quinemccluskey: invalid number of inputs")
+	// ErrTooManyInputs is returned when a form requiring every term of the
+	// inputs to be enumerated is selected for a function with more than
+	// maxtermInputLimit inputs.
+	ErrTooManyInputs = errors.New("quinemccluskey: too many inputs")
+	// ErrTooManyOutputs is returned when a function has more outputs than a
+	// reader or writer of a format can represent.
+	ErrTooManyOutputs = errors.New("quinemccluskey: too many outputs")
+	// ErrInvalidPLA is returned by ReadPLA when a PLA is malformed or uses a
+	// feature of the format which is not supported.
+	ErrInvalidPLA = errors.New("quinemccluskey: invalid PLA")
+	// ErrInvalidExpression is returned by ParseExpression when an expression
+	// is malformed or refers to an unknown input.
+	ErrInvalidExpression = errors.New("quinemccluskey: invalid expression")
+	// ErrInvalidNotation is returned by ParseSigmaPi when a function is not
+	// in valid sigma or pi notation.
+	ErrInvalidNotation = errors.New("quinemccluskey: invalid sigma or pi notation")
+	// ErrInvalidTruthTable is returned by ReadTruthTable when a truth table is
+	// malformed.
+	ErrInvalidTruthTable = errors.New("quinemccluskey: invalid truth table")
+	// ErrInvalidROM is returned by ReadROM when the contents of a ROM are
+	// malformed or do not fit its address and data widths.
+	ErrInvalidROM = errors.New("quinemccluskey: invalid ROM contents")
+	// ErrNoOutputs is returned when a LogicFunction with no outputs is solved.
+	ErrNoOutputs = errors.New("quinemccluskey: no outputs")
+	// ErrVerificationFailed is returned when a cover is found which is not a
+	// correct solution of the LogicFunction.
+	ErrVerificationFailed = errors.New("quinemccluskey: failed to yield a correct solution")
+)
@@ -0,0 +1,82 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddOutputErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs int
+		add    func(f *LogicFunction) error
+		want   error
+	}{
+		{"valid", 0, func(f *LogicFunction) error { return f.AddOutput([]uint64{1, 2}, []uint64{3}) }, nil},
+		{"conflicting terms", 0, func(f *LogicFunction) error { return f.AddOutput([]uint64{1, 2}, []uint64{2}) }, ErrConflictingTerms},
+		{"term too large", 2, func(f *LogicFunction) error { return f.AddOutput([]uint64{4}, nil) }, ErrInvalidTerm},
+		{"don't care too large", 2, func(f *LogicFunction) error { return f.AddOutput(nil, []uint64{7}) }, ErrInvalidTerm},
+		{"valid terms", 0, func(f *LogicFunction) error { return f.AddOutputTerms([]string{"01"}, []string{"11"}) }, nil},
+		{"invalid term", 0, func(f *LogicFunction) error { return f.AddOutputTerms([]string{"0x"}, nil) }, ErrInvalidTerm},
+		{"invalid don't care", 0, func(f *LogicFunction) error { return f.AddOutputTerms(nil, []string{"2"}) }, ErrInvalidTerm},
+		{"conflicting strings", 0, func(f *LogicFunction) error { return f.AddOutputTerms([]string{"01"}, []string{"001"}) }, ErrConflictingTerms},
+		{"string too long", 2, func(f *LogicFunction) error { return f.AddOutputTerms([]string{"100"}, nil) }, ErrInvalidTerm},
+		{"leading zeros", 2, func(f *LogicFunction) error { return f.AddOutputTerms([]string{"0001"}, nil) }, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var f LogicFunction
+			f.Init(false)
+			if err := f.SetNumInputs(test.inputs); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := test.add(&f); !errors.Is(err, test.want) {
+				t.Errorf("error = %v, want %v", err, test.want)
+			}
+
+			// an output is only added if there is no error
+			r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+			if outputs := len(r.Outputs); (test.want == nil) != (outputs == 1) {
+				t.Errorf("%d outputs were added", outputs)
+			}
+		})
+	}
+}
+
+func TestSetNumInputsErrors(t *testing.T) {
+	var f LogicFunction
+	f.Init(false)
+	if err := f.SetNumInputs(-1); !errors.Is(err, ErrInvalidInputCount) {
+		t.Errorf("SetNumInputs(-1) = %v, want %v", err, ErrInvalidInputCount)
+	}
+	if err := f.AddOutput([]uint64{5}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetNumInputs(2); !errors.Is(err, ErrInvalidInputCount) {
+		t.Errorf("SetNumInputs(2) with the term 5 = %v, want %v", err, ErrInvalidInputCount)
+	}
+	if err := f.SetNumInputs(3); err != nil {
+		t.Errorf("SetNumInputs(3) with the term 5 = %v, want nil", err)
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	var f LogicFunction
+	f.Init(false)
+	if r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{}); !errors.Is(r.Err, ErrNoOutputs) {
+		t.Errorf("Result.Err with no outputs = %v, want %v", r.Err, ErrNoOutputs)
+	}
+
+	if err := f.SetNumInputs(maxtermInputLimit + 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddOutput([]uint64{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	f.SetForm(ProductOfSums)
+	if r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{}); !errors.Is(r.Err, ErrTooManyInputs) || r.String() != r.Err.Error() {
+		t.Errorf("Result.Err with %d inputs = %v, want %v", maxtermInputLimit+1, r.Err, ErrTooManyInputs)
+	}
+}
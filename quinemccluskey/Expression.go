@@ -0,0 +1,346 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Expression is a Boolean expression of the inputs of a LogicFunction, parsed
+// by ParseExpression. The zero Expression is the constant 0.
+type Expression struct {
+	root *exprNode
+}
+
+// exprOp is the operation performed by a node of an Expression.
+type exprOp int
+
+const (
+	exprConst exprOp = iota
+	exprInput
+	exprNot
+	exprAnd
+	exprOr
+	exprXor
+	exprXnor
+)
+
+// exprNode is a node of the syntax tree of an Expression. Constants hold
+// their value, inputs hold their index, and operations hold their operands.
+type exprNode struct {
+	op       exprOp
+	value    bool
+	input    int
+	operands []*exprNode
+}
+
+// eval returns the value of the node for the term whose bits give the values
+// of the inputs.
+func (n *exprNode) eval(term uint64) bool {
+	switch n.op {
+	case exprConst:
+		return n.value
+	case exprInput:
+		return term>>n.input&1 == 1
+	case exprNot:
+		return !n.operands[0].eval(term)
+	case exprAnd:
+		return n.operands[0].eval(term) && n.operands[1].eval(term)
+	case exprOr:
+		return n.operands[0].eval(term) || n.operands[1].eval(term)
+	case exprXor:
+		return n.operands[0].eval(term) != n.operands[1].eval(term)
+	default:
+		return n.operands[0].eval(term) == n.operands[1].eval(term)
+	}
+}
+
+// numInputs returns the number of inputs needed to include every input the
+// node refers to.
+func (n *exprNode) numInputs() int {
+	if n.op == exprInput {
+		return n.input + 1
+	}
+
+	count := 0
+	for _, operand := range n.operands {
+		if c := operand.numInputs(); c > count {
+			count = c
+		}
+	}
+	return count
+}
+
+// exprToken is a token of an expression, with the offset at which it starts.
+type exprToken struct {
+	text   string
+	offset int
+}
+
+// exprParser is a recursive descent parser of the tokens of an expression.
+type exprParser struct {
+	tokens   []exprToken
+	pos      int
+	inLabels InputLabels
+}
+
+// ParseExpression parses a Boolean expression, such as "a.b' + c(a ^ d)",
+// resolving the names of its variables to inputs using inLabels. Inputs
+// without a label are named "x" followed by their index. From lowest to
+// highest precedence, the operators are:
+//
+//	| or +          OR
+//	^, or ~^ or ^~  XOR, or XNOR
+//	. or & or *     AND, which may also be written by juxtaposition
+//	~ or !          NOT, as a prefix
+//	'               NOT, as a suffix
+//
+// Parentheses group subexpressions, and 0 and 1 are constants. Names are
+// made of letters, digits and underscores, not starting with a digit, so
+// juxtaposed names must be separated, as in "a b" rather than "ab". If the
+// expression is malformed or refers to a name which is not a label,
+// ErrInvalidExpression is returned.
+func ParseExpression(text string, inLabels InputLabels) (Expression, error) {
+	tokens, err := tokenizeExpression(text)
+	if err != nil {
+		return Expression{}, err
+	}
+
+	p := exprParser{tokens: tokens, inLabels: inLabels}
+	root, err := p.parseOr()
+	if err != nil {
+		return Expression{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return Expression{}, p.unexpected()
+	}
+
+	return Expression{root}, nil
+}
+
+// tokenizeExpression splits an expression into names, constants and
+// operators, discarding white space.
+func tokenizeExpression(text string) ([]exprToken, error) {
+	tokens := []exprToken{}
+
+	for offset := 0; offset < len(text); {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+
+		switch {
+		case unicode.IsSpace(r):
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			size = strings.IndexFunc(text[offset:], func(r rune) bool {
+				return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+			if size < 0 {
+				size = len(text) - offset
+			}
+			tokens = append(tokens, exprToken{text[offset : offset+size], offset})
+		case strings.HasPrefix(text[offset:], "~^") || strings.HasPrefix(text[offset:], "^~"):
+			size = 2
+			tokens = append(tokens, exprToken{"~^", offset})
+		case strings.ContainsRune("+|^.&*~!'()", r):
+			tokens = append(tokens, exprToken{string(r), offset})
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidExpression, r, offset)
+		}
+
+		offset += size
+	}
+
+	return tokens, nil
+}
+
+// peek returns the text of the next token, or "" at the end of the tokens.
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].text
+	}
+	return ""
+}
+
+// unexpected returns an error describing the next token.
+func (p *exprParser) unexpected() error {
+	if p.pos < len(p.tokens) {
+		return fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidExpression, p.tokens[p.pos].text, p.tokens[p.pos].offset)
+	}
+	return fmt.Errorf("%w: unexpected end of expression", ErrInvalidExpression)
+}
+
+// parseOr parses a sum of one or more XORs.
+func (p *exprParser) parseOr() (*exprNode, error) {
+	left, err := p.parseXor()
+	for err == nil && (p.peek() == "+" || p.peek() == "|") {
+		p.pos++
+		var right *exprNode
+		right, err = p.parseXor()
+		left = &exprNode{op: exprOr, operands: []*exprNode{left, right}}
+	}
+	return left, err
+}
+
+// parseXor parses an XOR or XNOR of one or more products.
+func (p *exprParser) parseXor() (*exprNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && (p.peek() == "^" || p.peek() == "~^") {
+		op := exprXor
+		if p.peek() == "~^" {
+			op = exprXnor
+		}
+		p.pos++
+		var right *exprNode
+		right, err = p.parseAnd()
+		left = &exprNode{op: op, operands: []*exprNode{left, right}}
+	}
+	return left, err
+}
+
+// parseAnd parses a product of one or more factors, which may be joined by an
+// operator or juxtaposed.
+func (p *exprParser) parseAnd() (*exprNode, error) {
+	left, err := p.parseFactor()
+	for err == nil {
+		if next := p.peek(); next == "." || next == "&" || next == "*" {
+			p.pos++
+		} else if !startsFactor(next) {
+			break
+		}
+
+		var right *exprNode
+		right, err = p.parseFactor()
+		left = &exprNode{op: exprAnd, operands: []*exprNode{left, right}}
+	}
+	return left, err
+}
+
+// startsFactor reports whether a factor can start with the passed token.
+func startsFactor(token string) bool {
+	if token == "" {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(token)
+	return token == "~" || token == "!" || token == "(" || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// parseFactor parses a name, constant or parenthesized expression, which may
+// be complemented by prefix or suffix NOT operators.
+func (p *exprParser) parseFactor() (*exprNode, error) {
+	var node *exprNode
+
+	switch next := p.peek(); {
+	case next == "~" || next == "!":
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNode{op: exprNot, operands: []*exprNode{operand}}, nil
+	case next == "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, p.unexpected()
+		}
+		p.pos++
+		node = inner
+	case next == "0" || next == "1":
+		p.pos++
+		node = &exprNode{op: exprConst, value: next == "1"}
+	case startsFactor(next) && !unicode.IsDigit(rune(next[0])):
+		input, ok := p.inLabels.Index(next)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown input %q at offset %d", ErrInvalidExpression, next, p.tokens[p.pos].offset)
+		}
+		p.pos++
+		node = &exprNode{op: exprInput, input: input}
+	default:
+		return nil, p.unexpected()
+	}
+
+	for p.peek() == "'" {
+		p.pos++
+		node = &exprNode{op: exprNot, operands: []*exprNode{node}}
+	}
+
+	return node, nil
+}
+
+// NumInputs returns the fewest inputs a LogicFunction must have to include
+// every input the expression refers to.
+func (e Expression) NumInputs() int {
+	if e.root == nil {
+		return 0
+	}
+	return e.root.numInputs()
+}
+
+// eval returns the value of the expression for the passed term.
+func (e Expression) eval(term uint64) bool {
+	return e.root != nil && e.root.eval(term)
+}
+
+// Minterms returns the terms of numInputs inputs for which the expression is
+// 1, in increasing order, as strings of '1's and '0's with the most
+// significant input first. If the expression refers to an input beyond
+// numInputs, ErrInvalidInputCount is returned, and if numInputs is more than
+// maxtermInputLimit, ErrTooManyInputs is returned.
+func (e Expression) Minterms(numInputs int) ([]string, error) {
+	terms, err := e.terms(numInputs)
+	if err != nil {
+		return nil, err
+	}
+	return binaryTerms(terms, numInputs), nil
+}
+
+// terms returns the terms of numInputs inputs for which the expression is 1,
+// in increasing order.
+func (e Expression) terms(numInputs int) ([]bitset, error) {
+	if numInputs < e.NumInputs() {
+		return nil, fmt.Errorf("%w: expression refers to %d inputs", ErrInvalidInputCount, e.NumInputs())
+	}
+	if numInputs > maxtermInputLimit {
+		return nil, fmt.Errorf("%w: %d inputs exceeds the limit of %d for expressions", ErrTooManyInputs, numInputs, maxtermInputLimit)
+	}
+
+	terms := []bitset{}
+	for term := uint64(0); term < 1<<numInputs; term++ {
+		if e.eval(term) {
+			terms = append(terms, newBitset(term))
+		}
+	}
+
+	return terms, nil
+}
+
+// AddOutputExpression will add an output to the LogicFunction whose minterms
+// are the terms for which expression is 1, and whose don't cares are the
+// terms for which dontCares is 1. Terms for which both are 1 are treated as
+// don't cares. As the terms depend on the number of inputs, it must first be
+// declared by SetNumInputs, or ErrInvalidInputCount is returned.
+func (solver *LogicFunction) AddOutputExpression(expression Expression, dontCares Expression) error {
+	if solver.numInputs == 0 {
+		return fmt.Errorf("%w: the number of inputs must be declared before adding an expression", ErrInvalidInputCount)
+	}
+
+	terms, err := expression.terms(solver.numInputs)
+	if err != nil {
+		return err
+	}
+	dontCareSet, err := dontCares.terms(solver.numInputs)
+	if err != nil {
+		return err
+	}
+
+	mintermSet := []bitset{}
+	for _, term := range terms {
+		if !dontCares.eval(term.word) {
+			mintermSet = append(mintermSet, term)
+		}
+	}
+
+	return solver.addOutput(mintermSet, dontCareSet, nil, solver.numInputs)
+}
@@ -0,0 +1,161 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	var inLabels InputLabels
+	inLabels.Set(2, "a")
+	inLabels.Set(1, "b")
+	inLabels.Set(0, "c")
+
+	// the minterms of the inputs a, b and c, with a most significant
+	tests := []struct {
+		expression string
+		minterms   string
+	}{
+		{"0", ""},
+		{"1", "000 001 010 011 100 101 110 111"},
+		{"a", "100 101 110 111"},
+		{"a'", "000 001 010 011"},
+		{"~a", "000 001 010 011"},
+		{"!a''", "000 001 010 011"},
+		{"a.b", "110 111"},
+		{"a & b", "110 111"},
+		{"a*b", "110 111"},
+		{"a b", "110 111"},
+		{"a(b + c)", "101 110 111"},
+		{"a + b", "010 011 100 101 110 111"},
+		{"a | b", "010 011 100 101 110 111"},
+		{"a ^ b", "010 011 100 101"},
+		{"a ~^ b", "000 001 110 111"},
+		{"a ^~ b", "000 001 110 111"},
+		{"a + b c", "011 100 101 110 111"},
+		{"a ^ b c", "011 100 101 110"},
+		{"a + b ^ c", "001 010 100 101 110 111"},
+		{"(a + b)'", "000 001"},
+		{"a b' + c", "001 011 100 101 111"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := ParseExpression(test.expression, inLabels)
+			if err != nil {
+				t.Fatal(err)
+			}
+			minterms, err := e.Minterms(3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(minterms, " "); got != test.minterms {
+				t.Errorf("Minterms(3) = %q, want %q", got, test.minterms)
+			}
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	var inLabels InputLabels
+	inLabels.Set(1, "a")
+	inLabels.Set(0, "b")
+
+	for _, expression := range []string{"", "a +", "a + + b", "(a", "a)", "()", "a $ b", "c", "a 2", "'a", "a ~", "ab"} {
+		t.Run(expression, func(t *testing.T) {
+			if _, err := ParseExpression(expression, inLabels); !errors.Is(err, ErrInvalidExpression) {
+				t.Errorf("ParseExpression(%q) error = %v, want %v", expression, err, ErrInvalidExpression)
+			}
+		})
+	}
+}
+
+func TestExpressionNumInputs(t *testing.T) {
+	var inLabels InputLabels
+	inLabels.Set(3, "d")
+	inLabels.Set(0, "a")
+
+	tests := []struct {
+		expression string
+		want       int
+	}{
+		{"1", 0},
+		{"a", 1},
+		{"a + x2", 3},
+		{"(a d)'", 4},
+	}
+
+	for _, test := range tests {
+		e, err := ParseExpression(test.expression, inLabels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := e.NumInputs(); got != test.want {
+			t.Errorf("NumInputs() of %q = %d, want %d", test.expression, got, test.want)
+		}
+	}
+
+	if got := (Expression{}).NumInputs(); got != 0 {
+		t.Errorf("NumInputs() of the zero Expression = %d, want 0", got)
+	}
+
+	e, err := ParseExpression("d", inLabels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Minterms(3); !errors.Is(err, ErrInvalidInputCount) {
+		t.Errorf("Minterms(3) of d error = %v, want %v", err, ErrInvalidInputCount)
+	}
+	if _, err := e.Minterms(maxtermInputLimit + 1); !errors.Is(err, ErrTooManyInputs) {
+		t.Errorf("Minterms(%d) error = %v, want %v", maxtermInputLimit+1, err, ErrTooManyInputs)
+	}
+}
+
+func TestAddOutputExpression(t *testing.T) {
+	var inLabels InputLabels
+	inLabels.Set(1, "a")
+	inLabels.Set(0, "b")
+	expression, err := ParseExpression("a + b", inLabels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dontCares, err := ParseExpression("a b", inLabels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f LogicFunction
+	f.Init(false)
+	if err := f.AddOutputExpression(expression, dontCares); !errors.Is(err, ErrInvalidInputCount) {
+		t.Errorf("AddOutputExpression() before SetNumInputs error = %v, want %v", err, ErrInvalidInputCount)
+	}
+
+	if err := f.SetNumInputs(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddOutputExpression(expression, dontCares); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddOutputExpression(Expression{}, Expression{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the terms for which both are 1 are don't cares
+	checkResult(t, f.GetMinimumCostCover(inLabels, OutputLabels{}), []testOutput{
+		{minterms: []uint64{1, 2}, dontCares: []uint64{3}},
+		{},
+	})
+
+	var wide LogicFunction
+	wide.Init(false)
+	if err := wide.SetNumInputs(maxtermInputLimit + 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := wide.AddOutputExpression(expression, Expression{}); !errors.Is(err, ErrTooManyInputs) {
+		t.Errorf("AddOutputExpression() of %d inputs error = %v, want %v", maxtermInputLimit+1, err, ErrTooManyInputs)
+	}
+	if len(wide.minterms) != 0 {
+		t.Errorf("an output was added with an error")
+	}
+}
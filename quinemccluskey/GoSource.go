@@ -0,0 +1,338 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// GoOptions controls the Go source written by WriteGo, and the test written
+// by WriteGoTest.
+type GoOptions struct {
+	// Package is the name of the package of the generated files. If empty,
+	// the package is named "logic".
+	Package string
+	// Combined selects a single function returning the outputs as the bits
+	// of a uint64, with the first output most significant, rather than a
+	// function for each output.
+	Combined bool
+	// FunctionName is the name of the combined function, and of the test,
+	// prefixed by "Test". If empty, the name "LogicFunction" is used.
+	FunctionName string
+}
+
+// goReserved holds the keywords and predeclared identifiers of Go, which are
+// not used as identifiers, and the identifiers used by the generated code.
+var goReserved = map[string]bool{}
+
+func init() {
+	for _, identifier := range strings.Fields(`break case chan const continue
+		default defer else fallthrough for func go goto if import interface map
+		package range return select struct switch type var any append bool byte
+		cap clear close comparable complex complex64 complex128 copy delete
+		error false float32 float64 imag int int8 int16 int32 int64 iota len
+		make max min new nil panic print println real recover rune string true
+		uint uint8 uint16 uint32 uint64 uintptr x y t o got want ok value
+		values labels testing`) {
+		goReserved[identifier] = true
+	}
+}
+
+// goIdentifier returns a legal Go identifier for a label, in which each run of
+// characters that may not appear in an identifier is replaced by a single
+// '_', or dropped at either end of the label, so "addr[3]" becomes "addr_3".
+// Labels which do not start with a letter, and reserved identifiers, are
+// prefixed by "_", and an empty label becomes "v".
+func goIdentifier(label string) string {
+	identifier := []rune{}
+	replaced := false
+	for _, r := range label {
+		if !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			replaced = true
+			continue
+		}
+		if replaced && len(identifier) > 0 {
+			identifier = append(identifier, '_')
+		}
+		identifier, replaced = append(identifier, r), false
+	}
+
+	if len(identifier) == 0 {
+		return "v"
+	}
+	if !unicode.IsLetter(identifier[0]) || goReserved[string(identifier)] {
+		return "_" + string(identifier)
+	}
+
+	return string(identifier)
+}
+
+// goTerm returns a Go expression for an implicant of the input x, as the
+// product of its literals, such as "(x>>a)&(^x>>b)", or for the ProductOfSums
+// form, the sum of its complemented literals, such as "(^x>>a)|(x>>b)". The
+// passed constants give the position of each input bit in x. Bit 0 of the
+// expression is the value of the term.
+func goTerm(im Implicant, form Form, constants []string) string {
+	literals := []string{}
+	for bit := im.width - 1; bit >= 0; bit-- {
+		if im.im.xMask.bit(bit) {
+			continue
+		}
+		if im.im.literals.bit(bit) == (form == ProductOfSums) {
+			literals = append(literals, "(^x>>"+constants[bit]+")")
+		} else {
+			literals = append(literals, "(x>>"+constants[bit]+")")
+		}
+	}
+
+	if form == ProductOfSums {
+		if len(literals) == 0 {
+			return "0"
+		}
+		return strings.Join(literals, " | ")
+	}
+
+	if len(literals) == 0 {
+		return "1"
+	}
+	return strings.Join(literals, " & ")
+}
+
+// goNames holds the identifiers chosen for the Go source of a Result.
+type goNames struct {
+	pkg      string
+	function string
+	// inputs holds the constant giving the position of each input bit, and
+	// outputs holds the function of each output or, for a combined function,
+	// the constant giving the position of its bit.
+	inputs  []string
+	outputs []string
+	used    map[string]bool
+}
+
+// newGoNames chooses the identifiers of the Go source of a Result.
+func newGoNames(r Result, options GoOptions) goNames {
+	names := goNames{
+		pkg:      options.Package,
+		function: options.FunctionName,
+		inputs:   make([]string, r.NumInputs),
+		outputs:  make([]string, len(r.Outputs)),
+		used:     map[string]bool{},
+	}
+	if names.pkg == "" {
+		names.pkg = "logic"
+	}
+	if names.function == "" {
+		names.function = "LogicFunction"
+	}
+
+	key := func(s string) string { return s }
+	names.function = uniqueIdentifier(goIdentifier(names.function), names.used, key)
+	for i := r.NumInputs - 1; i >= 0; i-- {
+		names.inputs[i] = uniqueIdentifier(goIdentifier(r.inLabels.Str(i)), names.used, key)
+	}
+	for o := range r.Outputs {
+		names.outputs[o] = uniqueIdentifier(goIdentifier(r.outLabels.Str(o)), names.used, key)
+	}
+
+	return names
+}
+
+// writeGoSource formats Go source and writes it to w.
+func writeGoSource(w io.Writer, source string) error {
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(formatted)
+	return err
+}
+
+// WriteGo writes the cover held by a Result to w as a Go source file, in
+// which each output is computed without branches by bitwise operations on a
+// uint64 holding the inputs, with input i as bit i. A constant named from the
+// label of each input gives its position. Each output is a function named
+// from its label returning 0 or 1, or if options.Combined is set, a bit of
+// the value returned by a single function, with a constant named from its
+// label giving its position. Labels are sanitized to legal identifiers, and
+// made unique. If the function has more than 64 inputs, ErrTooManyInputs is
+// returned, if options.Combined is set for more than 64 outputs,
+// ErrTooManyOutputs is returned, and if the Result's Err is set, it is
+// returned.
+func WriteGo(w io.Writer, r Result, options GoOptions) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if r.NumInputs > 64 {
+		return fmt.Errorf("%w: %d inputs exceeds the limit of 64 for Go source", ErrTooManyInputs, r.NumInputs)
+	}
+	if options.Combined && len(r.Outputs) > 64 {
+		return fmt.Errorf("%w: %d outputs exceeds the limit of 64 for a combined function", ErrTooManyOutputs, len(r.Outputs))
+	}
+
+	names := newGoNames(r, options)
+	key := func(s string) string { return s }
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// Code generated by tabular_method; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", names.pkg)
+
+	if r.NumInputs > 0 {
+		fmt.Fprintf(&b, "// The position of each input in the argument of the functions.\n")
+		fmt.Fprintf(&b, "const (\n")
+		for i := r.NumInputs - 1; i >= 0; i-- {
+			fmt.Fprintf(&b, "%s = %d\n", names.inputs[i], i)
+		}
+		fmt.Fprintf(&b, ")\n\n")
+	}
+
+	// equation returns the expression of an output, with the passed names
+	// of shared terms
+	equation := func(output OutputResult, shared map[sharedTerm]string) string {
+		// a term of no literals is the constant 1 in a sum of products, and
+		// 0 in a product of sums
+		terms := []string{}
+		for _, im := range output.Implicants {
+			if im.LiteralCount() == 0 {
+				return goTerm(im, output.Form, names.inputs)
+			}
+			term := goTerm(im, output.Form, names.inputs)
+			if name, ok := shared[sharedTerm{output.Form, im.String()}]; ok {
+				term = name
+			} else if output.Form == ProductOfSums && len(output.Implicants) > 1 && im.LiteralCount() > 1 {
+				term = "(" + term + ")"
+			}
+			terms = append(terms, term)
+		}
+
+		switch {
+		case output.Form == ProductOfSums && len(terms) == 0:
+			return "1"
+		case output.Form == ProductOfSums:
+			return "(" + strings.Join(terms, " & ") + ") & 1"
+		case len(terms) == 0:
+			return "0"
+		default:
+			return "(" + strings.Join(terms, " | ") + ") & 1"
+		}
+	}
+
+	if !options.Combined {
+		for o, output := range r.Outputs {
+			fmt.Fprintf(&b, "// %s returns the output %s of the inputs x, as 0 or 1.\n", names.outputs[o], r.outLabels.Str(o))
+			fmt.Fprintf(&b, "func %s(x uint64) uint64 {\n", names.outputs[o])
+			fmt.Fprintf(&b, "return %s\n", equation(output, nil))
+			fmt.Fprintf(&b, "}\n\n")
+		}
+
+		return writeGoSource(w, b.String())
+	}
+
+	// the first output is the most significant bit
+	fmt.Fprintf(&b, "// The position of each output in the value returned by %s.\n", names.function)
+	fmt.Fprintf(&b, "const (\n")
+	for o := range r.Outputs {
+		fmt.Fprintf(&b, "%s = %d\n", names.outputs[o], len(r.Outputs)-1-o)
+	}
+	fmt.Fprintf(&b, ")\n\n")
+
+	fmt.Fprintf(&b, "// %s returns the outputs of the inputs x.\n", names.function)
+	fmt.Fprintf(&b, "func %s(x uint64) uint64 {\n", names.function)
+
+	terms, implicants := sharedTerms(r)
+	shared := sharedNames(terms, names.used, key)
+	for _, term := range terms {
+		fmt.Fprintf(&b, "%s := %s\n", shared[term], goTerm(implicants[term], term.form, names.inputs))
+	}
+	if len(terms) > 0 {
+		fmt.Fprintf(&b, "\n")
+	}
+
+	fmt.Fprintf(&b, "var y uint64\n")
+	for o, output := range r.Outputs {
+		fmt.Fprintf(&b, "y |= (%s) << %s\n", equation(output, shared), names.outputs[o])
+	}
+	fmt.Fprintf(&b, "return y\n}\n")
+
+	return writeGoSource(w, b.String())
+}
+
+// WriteGoTest writes to w a Go test of the source written by WriteGo with the
+// same options, which checks the outputs for every term of the inputs against
+// the function whose minterms and don't cares were added to the
+// LogicFunction. If the function has more than maxtermInputLimit inputs,
+// ErrTooManyInputs is returned, if options.Combined is set for more than 64
+// outputs, ErrTooManyOutputs is returned, and if the Result's Err is set, it
+// is returned.
+func WriteGoTest(w io.Writer, r Result, options GoOptions) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if r.NumInputs > maxtermInputLimit {
+		return fmt.Errorf("%w: %d inputs exceeds the limit of %d for a test", ErrTooManyInputs, r.NumInputs, maxtermInputLimit)
+	}
+	if options.Combined && len(r.Outputs) > 64 {
+		return fmt.Errorf("%w: %d outputs exceeds the limit of 64 for a combined function", ErrTooManyOutputs, len(r.Outputs))
+	}
+
+	// a test is named "Test" followed by a character which is not lower case
+	names := newGoNames(r, options)
+	test := []rune(strings.TrimLeft(names.function, "_"))
+	if len(test) == 0 {
+		test = []rune("LogicFunction")
+	}
+	test[0] = unicode.ToUpper(test[0])
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// Code generated by tabular_method; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", names.pkg)
+	fmt.Fprintf(&b, "import \"testing\"\n\n")
+
+	fmt.Fprintf(&b, "func Test%s(t *testing.T) {\n", string(test))
+
+	// the expected outputs of each term are a string with the first output
+	// first, where '-' is a don't care
+	terms, values, defaults := r.expectedValues()
+	fmt.Fprintf(&b, "want := map[uint64]string{\n")
+	for _, term := range terms {
+		if values[term] != defaults {
+			fmt.Fprintf(&b, "%s: %q,\n", term.decimal(), values[term])
+		}
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	outputs := []string{}
+	for o := range r.Outputs {
+		outputs = append(outputs, fmt.Sprintf("%q", r.outLabels.Str(o)))
+	}
+	fmt.Fprintf(&b, "labels := []string{%s}\n\n", strings.Join(outputs, ", "))
+
+	fmt.Fprintf(&b, "for x := uint64(0); x < 1<<%d; x++ {\n", r.NumInputs)
+	if options.Combined {
+		fmt.Fprintf(&b, "y := %s(x)\n", names.function)
+		fmt.Fprintf(&b, "got := []uint64{")
+		for o := range r.Outputs {
+			fmt.Fprintf(&b, "y >> %s & 1, ", names.outputs[o])
+		}
+		fmt.Fprintf(&b, "}\n")
+	} else {
+		fmt.Fprintf(&b, "got := []uint64{")
+		for o := range r.Outputs {
+			fmt.Fprintf(&b, "%s(x), ", names.outputs[o])
+		}
+		fmt.Fprintf(&b, "}\n")
+	}
+	fmt.Fprintf(&b, "values, ok := want[x]\n")
+	fmt.Fprintf(&b, "if !ok {\nvalues = %q\n}\n", defaults)
+	fmt.Fprintf(&b, "for o, value := range values {\n")
+	fmt.Fprintf(&b, "if value != '-' && got[o] != uint64(value-'0') {\n")
+	fmt.Fprintf(&b, "t.Errorf(\"output %%s of term %%d is %%d, want %%c\", labels[o], x, got[o], value)\n")
+	fmt.Fprintf(&b, "}\n}\n}\n}\n")
+
+	return writeGoSource(w, b.String())
+}
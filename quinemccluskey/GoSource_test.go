@@ -0,0 +1,88 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteGo(t *testing.T) {
+	tests := []struct {
+		name    string
+		form    Form
+		options GoOptions
+	}{
+		{"go_sop", SumOfProducts, GoOptions{}},
+		{"go_combined", SumOfProducts, GoOptions{Package: "decoder", Combined: true, FunctionName: "decode"}},
+		{"go_pos", ProductOfSums, GoOptions{Combined: true, FunctionName: "func"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := goldenResult(t, test.form)
+
+			var b strings.Builder
+			if err := WriteGo(&b, r, test.options); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, test.name, b.String())
+
+			b.Reset()
+			if err := WriteGoTest(&b, r, test.options); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, test.name+"_test", b.String())
+		})
+	}
+}
+
+func TestWriteGoErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        Result
+		options  GoOptions
+		want     error
+		wantTest error
+	}{
+		{"error", Result{Err: ErrNoOutputs}, GoOptions{}, ErrNoOutputs, ErrNoOutputs},
+		{"65 inputs", Result{NumInputs: 65}, GoOptions{}, ErrTooManyInputs, ErrTooManyInputs},
+		{"25 inputs", Result{NumInputs: maxtermInputLimit + 1}, GoOptions{}, nil, ErrTooManyInputs},
+		{"65 outputs", Result{Outputs: make([]OutputResult, 65)}, GoOptions{}, nil, nil},
+		{"65 combined outputs", Result{Outputs: make([]OutputResult, 65)}, GoOptions{Combined: true}, ErrTooManyOutputs, ErrTooManyOutputs},
+		{"64 combined outputs", Result{Outputs: make([]OutputResult, 64)}, GoOptions{Combined: true}, nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := WriteGo(&strings.Builder{}, test.r, test.options); !errors.Is(err, test.want) {
+				t.Errorf("WriteGo() error = %v, want %v", err, test.want)
+			}
+			if err := WriteGoTest(&strings.Builder{}, test.r, test.options); !errors.Is(err, test.wantTest) {
+				t.Errorf("WriteGoTest() error = %v, want %v", err, test.wantTest)
+			}
+		})
+	}
+}
+
+func TestWriteGoTestName(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"", "func TestLogicFunction("},
+		{"decode", "func TestDecode("},
+		{"_decode", "func TestDecode("},
+		{"_", "func TestLogicFunction("},
+	}
+
+	r := goldenResult(t, SumOfProducts)
+	for _, test := range tests {
+		var b strings.Builder
+		if err := WriteGoTest(&b, r, GoOptions{Combined: true, FunctionName: test.function}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(b.String(), test.want) {
+			t.Errorf("WriteGoTest() with FunctionName %q does not contain %q", test.function, test.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package quinemccluskey
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONObserver is an Observer which writes each step taken to minimize a
+// LogicFunction to Writer as a line of JSON, producing a newline-delimited
+// JSON trace. Each line is an object whose "event" member names the step:
+//
+//	output_added              {"output", "minterms", "dont_cares", "off_set"}
+//	minimize_started          {"form"}
+//	column_generated          {"column", "columns"}
+//	primes_found              {"primes"}
+//	cover_table_built         {"table"}
+//	essential_primes_removed  {"selected", "table"}
+//	implicant_selected        {"selected", "table"}
+//	minimum_cover_found       {"index", "cover"}
+//	espresso_step             {"step", "cover"}
+//
+// The "off_set" of an output is only present if it was given. Terms and
+// implicants are strings of '1's, '0's and 'x's with the most significant
+// input first, and implicants are accompanied by the list of outputs they
+// apply to. Each column is a list of groups of entries holding
+// an implicant, its outputs, and whether it is checked. A table holds the
+// remaining primes, the rows of minterms they must cover, and for each prime
+// the indices of the rows it covers. Each selection holds the implicant and
+// the reason it was selected, which is "essential" for a prime which is the
+// only prime covering the given minterm of the given output, or
+// "cover_solver" for a prime selected by the CoverSolver.
+//
+// Observe has a pointer receiver, so a *JSONObserver is passed to SetObserver,
+// and its Err is checked once the LogicFunction has been solved.
+type JSONObserver struct {
+	Writer io.Writer
+	// Err holds the first error returned by Writer, after which no further
+	// events are written.
+	Err error
+}
+
+type jsonImplicant struct {
+	Implicant string `json:"implicant"`
+	Outputs   []int  `json:"outputs"`
+}
+
+type jsonEntry struct {
+	jsonImplicant
+	Checked bool `json:"checked"`
+}
+
+type jsonRow struct {
+	Output  int    `json:"output"`
+	Minterm string `json:"minterm"`
+}
+
+type jsonTable struct {
+	Primes []jsonImplicant `json:"primes"`
+	Rows   []jsonRow       `json:"rows"`
+	Covers [][]int         `json:"covers"`
+}
+
+type jsonSelection struct {
+	jsonImplicant
+	Reason  string `json:"reason"`
+	Output  *int   `json:"output,omitempty"`
+	Minterm string `json:"minterm,omitempty"`
+}
+
+// jsonImplicants returns the passed implicants in their JSON representation.
+func jsonImplicants(implicants []Implicant) []jsonImplicant {
+	list := []jsonImplicant{}
+	for _, im := range implicants {
+		list = append(list, jsonImplicant{im.String(), im.Outputs()})
+	}
+	return list
+}
+
+// newJSONTable returns the passed cover table in its JSON representation.
+func newJSONTable(table CoverProblem) *jsonTable {
+	t := &jsonTable{jsonImplicants(table.Primes), []jsonRow{}, [][]int{}}
+	for _, row := range table.Rows {
+		t.Rows = append(t.Rows, jsonRow{row.Output, row.Minterm})
+	}
+	for _, covers := range table.Covers {
+		t.Covers = append(t.Covers, append([]int{}, covers...))
+	}
+	return t
+}
+
+// Observe writes a line of JSON describing the passed event to the
+// JSONObserver's Writer, unless writing a previous event failed.
+func (j *JSONObserver) Observe(event Event) {
+	if j.Err != nil {
+		return
+	}
+
+	var record map[string]interface{}
+
+	switch e := event.(type) {
+	case OutputAddedEvent:
+		record = map[string]interface{}{"event": "output_added", "output": e.Output, "minterms": e.Minterms, "dont_cares": e.DontCares}
+		if e.OffSet != nil {
+			record["off_set"] = e.OffSet
+		}
+	case MinimizeStartedEvent:
+		form := "sum_of_products"
+		if e.Form == ProductOfSums {
+			form = "product_of_sums"
+		}
+		record = map[string]interface{}{"event": "minimize_started", "form": form}
+	case ColumnGeneratedEvent:
+		columns := [][][]jsonEntry{}
+		for k, column := range e.Columns {
+			columns = append(columns, [][]jsonEntry{})
+			for _, group := range column {
+				entries := []jsonEntry{}
+				for _, entry := range group {
+					entries = append(entries, jsonEntry{jsonImplicant{entry.Implicant.String(), entry.Implicant.Outputs()}, entry.Checked})
+				}
+				columns[k] = append(columns[k], entries)
+			}
+		}
+		record = map[string]interface{}{"event": "column_generated", "column": e.Column, "columns": columns}
+	case PrimesFoundEvent:
+		record = map[string]interface{}{"event": "primes_found", "primes": jsonImplicants(e.Primes)}
+	case CoverTableBuiltEvent:
+		record = map[string]interface{}{"event": "cover_table_built", "table": newJSONTable(e.Table)}
+	case EssentialPrimesRemovedEvent:
+		selected := []jsonSelection{}
+		for _, essential := range e.Essential {
+			output := essential.Output
+			im := jsonImplicant{essential.Implicant.String(), essential.Implicant.Outputs()}
+			selected = append(selected, jsonSelection{im, "essential", &output, essential.Minterm})
+		}
+		record = map[string]interface{}{"event": "essential_primes_removed", "selected": selected, "table": newJSONTable(e.Table)}
+	case ImplicantSelectedEvent:
+		im := jsonImplicant{e.Implicant.String(), e.Implicant.Outputs()}
+		selected := []jsonSelection{{jsonImplicant: im, Reason: "cover_solver"}}
+		record = map[string]interface{}{"event": "implicant_selected", "selected": selected, "table": newJSONTable(e.Table)}
+	case MinimumCoverFoundEvent:
+		record = map[string]interface{}{"event": "minimum_cover_found", "index": e.Index, "cover": jsonImplicants(e.Cover)}
+	case EspressoStepEvent:
+		step := "initial_cover"
+		switch e.Step {
+		case EspressoReduceExpandIrredundant:
+			step = "reduce_expand_irredundant"
+		case EspressoLastGasp:
+			step = "last_gasp"
+		}
+		record = map[string]interface{}{"event": "espresso_step", "step": step, "cover": jsonImplicants(e.Cover)}
+	default:
+		return
+	}
+
+	j.Err = json.NewEncoder(j.Writer).Encode(record)
+}
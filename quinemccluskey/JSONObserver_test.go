@@ -0,0 +1,82 @@
+package quinemccluskey
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// failingWriter fails every write after the first n.
+type failingWriter struct {
+	n      int
+	writes int
+}
+
+var errWrite = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes > w.n {
+		return 0, errWrite
+	}
+	return len(p), nil
+}
+
+func TestJSONObserver(t *testing.T) {
+	var b strings.Builder
+	observer := &JSONObserver{Writer: &b}
+
+	var f LogicFunction
+	f.Init(false)
+	f.SetObserver(observer)
+	f.SetCoverSolver(PetrickSolver{})
+	if err := f.AddOutput([]uint64{0, 1, 2, 5, 6, 7}, []uint64{3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddOutputOffSet([]uint64{1}, []uint64{0}); err != nil {
+		t.Fatal(err)
+	}
+	f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+	if observer.Err != nil {
+		t.Fatalf("Err = %v", observer.Err)
+	}
+
+	events := []string{}
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %q is not valid JSON: %v", line, err)
+		}
+		events = append(events, record["event"].(string))
+
+		if record["event"] == "output_added" {
+			_, hasOffSet := record["off_set"]
+			if output := record["output"].(float64); hasOffSet != (output == 1) {
+				t.Errorf("output %v has off_set %v", output, record["off_set"])
+			}
+		}
+	}
+
+	want := "output_added output_added minimize_started primes_found cover_table_built essential_primes_removed"
+	if got := strings.Join(events, " "); !strings.HasPrefix(got, want) {
+		t.Errorf("events are %s, want %s followed by the selected implicants", got, want)
+	}
+}
+
+func TestJSONObserverError(t *testing.T) {
+	w := &failingWriter{n: 2}
+	observer := &JSONObserver{Writer: w}
+
+	var f LogicFunction
+	f.Init(false)
+	f.SetObserver(observer)
+	if err := f.AddOutput([]uint64{0, 1, 2, 5, 6, 7}, nil); err != nil {
+		t.Fatal(err)
+	}
+	f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+
+	if !errors.Is(observer.Err, errWrite) || w.writes != 3 {
+		t.Errorf("Err = %v after %d writes, want %v after 3 writes", observer.Err, w.writes, errWrite)
+	}
+}
@@ -1,46 +1,70 @@
 package quinemccluskey
 
 import (
-	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 type InputLabels struct {
-	labels [64]string
+	labels []string
 }
 
 func (l *InputLabels) Init() {
-	l.labels = [64]string{}
+	l.labels = []string{}
 }
 
 func (l *InputLabels) Set(x int, label string) {
-	if x >= len(l.labels) {
+	if x < 0 {
 		return
 	}
 
+	for x >= len(l.labels) {
+		l.labels = append(l.labels, "")
+	}
+
 	l.labels[x] = label
 }
 
 func (l InputLabels) Str(x int) string {
-	if l.labels[x] == "" {
+	if x >= len(l.labels) || l.labels[x] == "" {
 		return fmt.Sprintf("x%d", x)
 	}
 
 	return l.labels[x]
 }
 
+// Index returns the input with the passed label, where inputs without a label
+// are labelled "x" followed by their index, and reports whether there is one.
+func (l InputLabels) Index(label string) (int, bool) {
+	for x, name := range l.labels {
+		if name == label {
+			return x, true
+		}
+	}
+
+	if strings.HasPrefix(label, "x") {
+		x, err := strconv.Atoi(label[1:])
+		if err == nil && x >= 0 && l.Str(x) == label {
+			return x, true
+		}
+	}
+
+	return 0, false
+}
+
 type OutputLabels struct {
-	labels   [64]string
+	labels   []string
 	nOutputs int
 }
 
 func (l *OutputLabels) Init() {
-	l.labels = [64]string{}
+	l.labels = []string{}
 	l.nOutputs = 0
 }
 
 func (l *OutputLabels) Add(label string) {
-	l.labels[l.nOutputs] = label
+	l.labels = append(l.labels, label)
 	l.nOutputs++
 }
 
@@ -49,11 +73,7 @@ func (l OutputLabels) NumOutputs() int {
 }
 
 func (l OutputLabels) Str(x int) string {
-	if x > l.nOutputs {
-		panic(errors.New("quinemccluskey: x exceeds number of defined outputs"))
-	}
-
-	if l.labels[x] == "" {
+	if x >= len(l.labels) || l.labels[x] == "" {
 		return fmt.Sprintf("f%d", x)
 	}
 
@@ -1,183 +1,771 @@
 package quinemccluskey
 
 import (
-	"math"
+	"context"
+	"fmt"
+	"os"
 
 	"golang.org/x/exp/slices"
 )
 
+// Engine selects the algorithm used to minimize a LogicFunction.
+type Engine int
+
+const (
+	// QuineMcCluskey generates every prime implicant of the function, then
+	// selects primes from the resulting cover table using the CoverSolver.
+	QuineMcCluskey Engine = iota
+	// Espresso heuristically minimizes the function by iterating the
+	// EXPAND, IRREDUNDANT and REDUCE steps of the Espresso algorithm, followed
+	// by LAST_GASP, until the cost of the cover stops improving. It avoids
+	// generating every prime implicant, making it suitable for functions with
+	// many inputs, but the resulting cover is not guaranteed to be minimal.
+	Espresso
+)
+
+// Form selects whether the outputs of a LogicFunction are minimized as a sum
+// of products or as a product of sums.
+type Form int
+
+const (
+	// SumOfProducts minimizes the minterms of each output, producing an OR
+	// of AND terms.
+	SumOfProducts Form = iota
+	// ProductOfSums minimizes the maxterms of each output, producing an AND
+	// of OR clauses, as used by NOR-NOR implementations.
+	ProductOfSums
+	// AutoForm minimizes both forms, and uses whichever has the lowest cost
+	// under the CostModel, and then the fewest literals, for each output.
+	AutoForm
+)
+
 // LogicFunction represents a group of outputs to determing a minumum cost
 // cover for.
 type LogicFunction struct {
-	printoutsEnabled      bool
-	largestTerm           uint64
+	observer              Observer
+	progress              func(Progress)
+	engine                Engine
+	form                  Form
+	coverSolver           CoverSolver
+	costModel             CostModel
+	coverOptimal          bool
+	coverCost             float64
+	largestTerm           bitset
+	numInputs             int
+	inferredWidth         int
 	implicantDisplayWidth int
-	mintermDisplayWidth   int
-	minterms              [][]uint64
-	dontCares             [][]uint64
+	minterms              [][]bitset
+	dontCares             [][]bitset
+	offSets               [][]bitset
 	m_implicantTable      implicantTable
 	m_coverTable          coverTable
 }
 
-// Init zeroes all members of LogicFunction.
+// Init zeroes all members of LogicFunction. If enablePrintouts is set, each
+// step is printed to stdout by a TextObserver.
 func (solver *LogicFunction) Init(enablePrintouts bool) {
-	solver.printoutsEnabled = enablePrintouts
-	solver.largestTerm = 0
+	solver.observer = nil
+	solver.progress = nil
+	if enablePrintouts {
+		solver.observer = TextObserver{os.Stdout}
+	}
+	solver.engine = QuineMcCluskey
+	solver.form = SumOfProducts
+	solver.coverSolver = GreedySolver{}
+	solver.costModel = ProductTermCost{}
+	solver.coverOptimal = false
+	solver.coverCost = 0
+	solver.largestTerm = bitset{}
+	solver.numInputs = 0
+	solver.inferredWidth = 0
 	solver.implicantDisplayWidth = 0
-	solver.mintermDisplayWidth = 0
-	solver.minterms = [][]uint64{}
-	solver.dontCares = [][]uint64{}
+	solver.minterms = [][]bitset{}
+	solver.dontCares = [][]bitset{}
+	solver.offSets = [][]bitset{}
 	solver.m_implicantTable.init()
 }
 
+// SetObserver sets the Observer passed an Event for each step taken to
+// minimize the LogicFunction, replacing the TextObserver set by Init if
+// printouts are enabled. A nil Observer disables tracing.
+func (solver *LogicFunction) SetObserver(observer Observer) {
+	solver.observer = observer
+}
+
+// SetProgressFunc sets a function to be called with the Progress of
+// GetMinimumCostCover after each step taken to minimize the LogicFunction. It
+// is called synchronously, so should return quickly. A nil function disables
+// progress reporting.
+func (solver *LogicFunction) SetProgressFunc(progress func(Progress)) {
+	solver.progress = progress
+}
+
+// SetNumInputs declares the number of inputs of the LogicFunction. Otherwise
+// the number of inputs is inferred from the largest term of any output, or the
+// longest term passed to AddOutputTerms, so inputs which are '0' in every term
+// are dropped from the most significant end. Passing 0 restores inferring the
+// number of inputs. Once declared, a term of an output which is not less than
+// 2^n is rejected with ErrInvalidTerm. If n is negative, or a term of an
+// output already added is too large, ErrInvalidInputCount is returned and the
+// number of inputs is unchanged.
+func (solver *LogicFunction) SetNumInputs(n int) error {
+	if n < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidInputCount, n)
+	}
+	if n > 0 && solver.largestTerm.msbPos() > n {
+		return fmt.Errorf("%w: term %s requires more than %d inputs", ErrInvalidInputCount, solver.largestTerm.decimal(), n)
+	}
+
+	solver.numInputs = n
+	solver.implicantDisplayWidth = solver.inferredWidth
+	if n > 0 {
+		solver.implicantDisplayWidth = n
+	}
+
+	return nil
+}
+
+// SetEngine selects the algorithm used by GetMinimumCostCover to minimize the
+// LogicFunction. The default after Init is QuineMcCluskey.
+func (solver *LogicFunction) SetEngine(engine Engine) {
+	solver.engine = engine
+}
+
+// SetForm selects the form of the equations produced by GetMinimumCostCover.
+// The default after Init is SumOfProducts.
+func (solver *LogicFunction) SetForm(form Form) {
+	solver.form = form
+}
+
+// SetCoverSolver sets the CoverSolver used by GetMinimumCostCover to select
+// primes once the essential primes have been removed from the cover table. The
+// default after Init is GreedySolver.
+func (solver *LogicFunction) SetCoverSolver(coverSolver CoverSolver) {
+	solver.coverSolver = coverSolver
+}
+
+// SetCostModel sets the CostModel minimized by GetMinimumCostCover. The
+// default after Init is ProductTermCost. Between covers of equal cost, the
+// cover with the fewest literals is preferred.
+func (solver *LogicFunction) SetCostModel(model CostModel) {
+	solver.costModel = model
+}
+
+// CoverCost returns the cost of the cover found by the last call to
+// GetMinimumCostCover under the CostModel.
+func (solver *LogicFunction) CoverCost() float64 {
+	return solver.coverCost
+}
+
+// CoverIsOptimal reports whether the cover found by the last call to
+// GetMinimumCostCover is proven to be of minimum cost.
+func (solver *LogicFunction) CoverIsOptimal() bool {
+	return solver.coverOptimal
+}
+
 // AddOutput will add an output to the LogicFunction to be included in the
-// minimum cost cover.
-func (solver *LogicFunction) AddOutput(minterms []uint64, dontCares []uint64) int {
-	mintermSet := []uint64{}
-	dontCareSet := []uint64{}
+// minimum cost cover. An output with no minterms is the constant 0, and an
+// output whose minterms and don't cares include every term of the inputs is
+// the constant 1. If a term is given as both a minterm and a don't care,
+// ErrConflictingTerms is returned, and if a term is too large for the number
+// of inputs declared by SetNumInputs, ErrInvalidTerm is returned. In either
+// case the output is not added.
+func (solver *LogicFunction) AddOutput(minterms []uint64, dontCares []uint64) error {
+	mintermSet := []bitset{}
+	for _, minterm := range minterms {
+		mintermSet = append(mintermSet, newBitset(minterm))
+	}
+
+	dontCareSet := []bitset{}
+	for _, dontCare := range dontCares {
+		dontCareSet = append(dontCareSet, newBitset(dontCare))
+	}
+
+	return solver.addOutput(mintermSet, dontCareSet, nil, 0)
+}
+
+// AddOutputTerms will add an output to the LogicFunction to be included in the
+// minimum cost cover, with each minterm and don't care given as a string of
+// '1's and '0's with the most significant input first. This allows outputs of
+// functions with more than 64 inputs to be added. Unless declared by
+// SetNumInputs, the function is assumed to have at least as many inputs as the
+// longest term has digits. If any term is not a valid binary string, or is
+// too large for the declared number of inputs, ErrInvalidTerm is returned,
+// and if a term is given as both a minterm and a don't care,
+// ErrConflictingTerms is returned. In either case the output is not added.
+func (solver *LogicFunction) AddOutputTerms(minterms []string, dontCares []string) error {
+	width := 0
+	mintermSet, err := parseTerms(minterms, "minterm", &width)
+	if err != nil {
+		return err
+	}
+
+	dontCareSet, err := parseTerms(dontCares, "don't care", &width)
+	if err != nil {
+		return err
+	}
+
+	return solver.addOutput(mintermSet, dontCareSet, nil, width)
+}
+
+// AddOutputOffSet will add an output to the LogicFunction given by its
+// minterms and its off-set, the terms for which it must be 0. Unlike
+// AddOutput, every term which is in neither list is a don't care, so a
+// partially specified output may be minimized over a large number of inputs
+// without enumerating them. If a term is given as both a minterm and a term
+// of the off-set, ErrConflictingTerms is returned, and if a term is too large
+// for the number of inputs declared by SetNumInputs, ErrInvalidTerm is
+// returned. In either case the output is not added.
+func (solver *LogicFunction) AddOutputOffSet(minterms []uint64, offSet []uint64) error {
+	mintermSet := []bitset{}
+	for _, minterm := range minterms {
+		mintermSet = append(mintermSet, newBitset(minterm))
+	}
+
+	offSetTerms := []bitset{}
+	for _, term := range offSet {
+		offSetTerms = append(offSetTerms, newBitset(term))
+	}
+
+	return solver.addOutput(mintermSet, []bitset{}, offSetTerms, 0)
+}
+
+// AddOutputOffSetTerms is AddOutputOffSet with each term given as a string of
+// '1's and '0's with the most significant input first, as for AddOutputTerms.
+// If any term is not a valid binary string, ErrInvalidTerm is returned.
+func (solver *LogicFunction) AddOutputOffSetTerms(minterms []string, offSet []string) error {
+	width := 0
+	mintermSet, err := parseTerms(minterms, "minterm", &width)
+	if err != nil {
+		return err
+	}
+
+	offSetTerms, err := parseTerms(offSet, "off-set term", &width)
+	if err != nil {
+		return err
+	}
+
+	return solver.addOutput(mintermSet, []bitset{}, offSetTerms, width)
+}
+
+// parseTerms parses a list of terms given as strings of '1's and '0's,
+// raising width to the number of digits of the longest. The passed kind of
+// term describes an invalid term in the returned error.
+func parseTerms(terms []string, kind string, width *int) ([]bitset, error) {
+	set := []bitset{}
+	for _, s := range terms {
+		term, ok := parseBinary(s)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s %q", ErrInvalidTerm, kind, s)
+		}
+		set = append(set, term)
+		if len(s) > *width {
+			*width = len(s)
+		}
+	}
+	return set, nil
+}
+
+// addOutput adds an output with the passed minterms and don't cares to the
+// LogicFunction, for a function of at least width inputs. If offSet is not
+// nil, it is the off-set of the output, and every term in none of the lists
+// is a don't care.
+func (solver *LogicFunction) addOutput(minterms []bitset, dontCares []bitset, offSet []bitset, width int) error {
+	mintermSet := []bitset{}
+	dontCareSet := []bitset{}
+	var offSetTerms []bitset
+
+	// every term must be a term of the declared inputs
+	if solver.numInputs > 0 {
+		for _, term := range append(append(append([]bitset{}, minterms...), dontCares...), offSet...) {
+			if term.msbPos() > solver.numInputs {
+				return fmt.Errorf("%w: %s is not a term of %d inputs", ErrInvalidTerm, term.decimal(), solver.numInputs)
+			}
+		}
+	}
 
 	// copy minterms into a sorted set
 	for _, minterm := range minterms {
 		mintermSet = insert(mintermSet, minterm, func(i int) bool {
-			return mintermSet[i] >= minterm
+			return !mintermSet[i].less(minterm)
 		})
 	}
 
 	// copy dontCares into a sorted set
 	for _, dontCare := range dontCares {
-		// exclude don't cares that appear in minterms
+		// a term cannot be both a minterm and a don't care
 		if slices.Contains(mintermSet, dontCare) {
-			dontCareSet = insert(dontCareSet, dontCare, func(i int) bool {
-				return dontCareSet[i] >= dontCare
-			})
+			return fmt.Errorf("%w: %s is both a minterm and a don't care of output %d", ErrConflictingTerms, dontCare.decimal(), len(solver.minterms))
 		}
-	}
 
-	// attempt to add the output to the implicant table
-	status := solver.m_implicantTable.addOutput(mintermSet, dontCareSet, solver.printoutsEnabled)
-	if status == 0 {
-		// save the added outputs
-		solver.minterms = append(solver.minterms, mintermSet)
-		solver.dontCares = append(solver.dontCares, dontCareSet)
+		dontCareSet = insert(dontCareSet, dontCare, func(i int) bool {
+			return !dontCareSet[i].less(dontCare)
+		})
+	}
 
-		// update saved display widths for printouts
+	// copy the off-set, if there is one, into a sorted set
+	if offSet != nil {
+		offSetTerms = []bitset{}
+		isMinterm := map[bitset]bool{}
 		for _, minterm := range mintermSet {
-			solver.largestTerm = uint64(math.Max(float64(solver.largestTerm), float64(minterm)))
+			isMinterm[minterm] = true
 		}
-		for _, dontCare := range dontCareSet {
-			solver.largestTerm = uint64(math.Max(float64(solver.largestTerm), float64(dontCare)))
+
+		for _, term := range offSet {
+			// a term cannot be both a minterm and a term of the off-set
+			if isMinterm[term] {
+				return fmt.Errorf("%w: %s is both a minterm and an off-set term of output %d", ErrConflictingTerms, term.decimal(), len(solver.minterms))
+			}
+
+			offSetTerms = insert(offSetTerms, term, func(i int) bool {
+				return !offSetTerms[i].less(term)
+			})
+		}
+	}
+
+	// save the added outputs
+	solver.minterms = append(solver.minterms, mintermSet)
+	solver.dontCares = append(solver.dontCares, dontCareSet)
+	solver.offSets = append(solver.offSets, offSetTerms)
+
+	// update the number of inputs of the function
+	for _, term := range append(append(append([]bitset{}, mintermSet...), dontCareSet...), offSetTerms...) {
+		if solver.largestTerm.less(term) {
+			solver.largestTerm = term
 		}
-		solver.implicantDisplayWidth = msbPos(uint64(solver.largestTerm))
-		solver.mintermDisplayWidth = 1 + int(math.Ceil(math.Log10(float64(solver.largestTerm))))
+	}
+	if msb := solver.largestTerm.msbPos(); msb > width {
+		width = msb
+	}
+	if width > solver.inferredWidth {
+		solver.inferredWidth = width
+	}
+	solver.implicantDisplayWidth = solver.inferredWidth
+	if solver.numInputs > 0 {
+		solver.implicantDisplayWidth = solver.numInputs
 	}
 
-	return status
+	notify(solver.observer, solver.outputAddedEvent(len(solver.minterms)-1, mintermSet, dontCareSet, offSetTerms))
+
+	return nil
 }
 
-// testOutput will return the outptut for a given input for a cover defined by
-// the list of passed implicants.
-func testOutput(implicants []implicant, output int, input uint64) bool {
-	for _, im := range implicants {
-		if (im.tag>>output)&1 == 1 && im.covers(input) {
+// outputAddedEvent returns an OutputAddedEvent for the passed output and its
+// terms, where offSet is nil unless the off-set of the output was given.
+func (solver *LogicFunction) outputAddedEvent(output int, minterms []bitset, dontCares []bitset, offSet []bitset) OutputAddedEvent {
+	event := OutputAddedEvent{Output: output, Minterms: binaryTerms(minterms, solver.implicantDisplayWidth), DontCares: binaryTerms(dontCares, solver.implicantDisplayWidth)}
+	if offSet != nil {
+		event.OffSet = binaryTerms(offSet, solver.implicantDisplayWidth)
+	}
+	return event
+}
+
+// anyOffSet reports whether any of the passed off-sets is given, in which
+// case the don't cares of that output are not listed.
+func anyOffSet(offSets [][]bitset) bool {
+	for _, offSet := range offSets {
+		if offSet != nil {
 			return true
 		}
 	}
-
 	return false
 }
 
-// verify cover will test a list of passed implicants against the defined
-// outputs for a LogicFunction and return a boolean for whether the list is a
-// correct cover of the function.
-func (solver *LogicFunction) verifyCover(cover []implicant) bool {
-	testPassed := true
+// maxtermInputLimit is the greatest number of inputs for which the maxterms of
+// a function are enumerated.
+const maxtermInputLimit = 24
+
+// maxterms returns, for each output, a sorted set of the terms which are
+// neither minterms nor don't cares. Where the off-set of an output was given,
+// it is returned, but otherwise every term of the inputs is enumerated, so is
+// only practical for functions with few inputs.
+func (solver *LogicFunction) maxterms() [][]bitset {
+	maxterms := [][]bitset{}
+
+	for output := range solver.minterms {
+		if solver.offSets[output] != nil {
+			maxterms = append(maxterms, solver.offSets[output])
+			continue
+		}
 
-	for output := 0; output < solver.m_implicantTable.nOutputs; output++ {
-		for input := 0; uint64(input) < (bitLimit(uint64(solver.largestTerm)) + 1); input++ {
-			// do not test don't cares
-			if slices.Contains(solver.dontCares[output], uint64(input)) {
+		maxterms = append(maxterms, []bitset{})
+
+		cares := map[bitset]bool{}
+		for _, term := range append(append([]bitset{}, solver.minterms[output]...), solver.dontCares[output]...) {
+			cares[term] = true
+		}
+
+		for term := (bitset{}); term.msbPos() <= solver.implicantDisplayWidth; term = term.increment() {
+			if !cares[term] {
+				maxterms[output] = append(maxterms[output], term)
+			}
+		}
+	}
+
+	return maxterms
+}
+
+// verify cover will test a list of passed implicants against the passed
+// minterms and don't cares of each output and return a boolean for whether
+// the list is a correct cover of the function. Rather than evaluating the
+// cover for every input, every minterm is checked to be covered, and every
+// implicant is checked to cover only minterms and don't cares, by counting
+// the minterms and don't cares that it covers. Where the off-set of an output
+// is given, its implicants are instead checked to cover no term of the
+// off-set.
+func (solver *LogicFunction) verifyCover(cover []implicant, minterms [][]bitset, dontCares [][]bitset, offSets [][]bitset) bool {
+	for output := 0; output < len(minterms); output++ {
+		// every minterm must be covered by an implicant of the output
+	NEXT_MINTERM:
+		for _, minterm := range minterms[output] {
+			for _, im := range cover {
+				if im.tag.bit(output) && im.covers(minterm) {
+					continue NEXT_MINTERM
+				}
+			}
+			return false
+		}
+
+		if offSets[output] != nil {
+			for _, im := range cover {
+				if !im.tag.bit(output) {
+					continue
+				}
+				for _, term := range offSets[output] {
+					if im.covers(term) {
+						return false
+					}
+				}
+			}
+			continue
+		}
+
+		cares := map[bitset]bool{}
+		for _, term := range append(append([]bitset{}, minterms[output]...), dontCares[output]...) {
+			cares[term] = true
+		}
+
+		// an implicant with k xMask bits covers 2^k terms, all of which must
+		// be minterms or don't cares of the output
+		for _, im := range cover {
+			if !im.tag.bit(output) {
 				continue
 			}
 
-			// determine expected output from minterms
-			expectedOutput := false
-			if slices.Contains(solver.minterms[output], uint64(input)) {
-				expectedOutput = true
+			k := im.xMask.count()
+			if k >= 63 {
+				return false
+			}
+
+			covered := 0
+			for term := range cares {
+				if im.covers(term) {
+					covered++
+				}
+			}
+			if covered != 1<<k {
+				return false
 			}
+		}
+	}
+
+	return true
+}
+
+// outputCost returns the cost under the CostModel of the implicants in the
+// passed cover which apply to output, considering only that output, and the
+// total number of literals in those implicants.
+func (solver LogicFunction) outputCost(cover []implicant, output int) (float64, int) {
+	cost, literals := 0.0, 0
+	for _, im := range cover {
+		if im.tag.bit(output) {
+			single := Implicant{implicant{im.literals, im.xMask, singleBit(output), false}, solver.implicantDisplayWidth}
+			cost += solver.costModel.ImplicantCost(single)
+			literals += single.LiteralCount()
+		}
+	}
+	return cost, literals
+}
 
-			// compare output of cover against expected output
-			if testOutput(cover, output, uint64(input)) != expectedOutput {
-				testPassed = false
+// formCost returns the cost under the CostModel of the implicants of the
+// passed cover, considering only the outputs rendered in the passed form.
+func (solver LogicFunction) formCost(cover []implicant, forms []Form, form Form) float64 {
+	used := []implicant{}
+	for _, im := range cover {
+		for output, f := range forms {
+			if f != form {
+				im.tag = im.tag.without(output)
 			}
 		}
+		if !im.tag.isZero() {
+			used = append(used, im)
+		}
 	}
+	return totalCost(used, solver.costModel, solver.implicantDisplayWidth)
+}
+
+// minimize finds covers of the passed minterms and don't cares of each output
+// using the selected engine, returning the covers and whether they are proven
+// to be of minimum cost. Where the off-set of an output is given, its
+// remaining terms are don't cares. Where limit is other than 1 and the
+// CoverSolver is a CoverEnumerator, every minimum cost cover is returned, up
+// to limit covers or all of them if limit is 0. Otherwise a single cover is
+// returned. For QuineMcCluskey, the passed implicantTable must hold the same
+// outputs unless an off-set is given, and is solved using the passed
+// coverTable. Each step is passed to the observer. If the context is done,
+// minimization stops early and the returned covers are incomplete.
+func (solver *LogicFunction) minimize(ctx context.Context, observer Observer, iTable *implicantTable, cTable *coverTable, minterms [][]bitset, dontCares [][]bitset, offSets [][]bitset, limit int) ([][]implicant, bool) {
+	switch solver.engine {
+	case Espresso:
+		// heuristically minimize the outputs without generating every prime
+		return [][]implicant{espressoCover(ctx, minterms, dontCares, offSets, solver.costModel, solver.implicantDisplayWidth, observer)}, false
+	default:
+		// the don't cares of an output with an off-set are not listed, so
+		// the primes are found by expanding each minterm against the
+		// off-sets. Otherwise the implicant table is reduced to identify the
+		// primes
+		var primeImplicants []implicant
+		if anyOffSet(offSets) {
+			primeImplicants = offSetPrimes(ctx, minterms, dontCares, offSets, solver.implicantDisplayWidth, observer)
+		} else {
+			primeImplicants = iTable.reduce(ctx, solver.implicantDisplayWidth, observer)
+		}
+		cTable.build(ctx, minterms, primeImplicants)
+		if ctx.Err() != nil {
+			return [][]implicant{nil}, false
+		}
 
-	return testPassed
+		// enumerate the minimum cost covers of the cover table
+		if enumerator, ok := solver.coverSolver.(CoverEnumerator); ok && limit != 1 {
+			return cTable.getAllMinimumCostCovers(ctx, enumerator, limit, solver.costModel, solver.implicantDisplayWidth, observer)
+		}
+
+		// solve the cover table of prime implicants for a minimum cost cover
+		cover, optimal := cTable.getMinimumCostCover(ctx, solver.coverSolver, solver.costModel, solver.implicantDisplayWidth, observer)
+		return [][]implicant{cover}, optimal
+	}
 }
 
-// stringifyLogicFunction returns a string representation of a logic function
-// descibed by the passed list of implicants. Outputs and input bits are
-// printed using the labels described in InputLabels and OutputLabels.
-func (solver LogicFunction) stringifyLogicFunction(implicants []implicant, inLabels InputLabels, outLabels OutputLabels) string {
-	outputEquations := ""
+// result builds a Result from the passed covers of the minterms and maxterms
+// of each output, using forms to select the cover of each output. Essential
+// primes are identified from the passed coverTables.
+func (solver *LogicFunction) result(sopCover []implicant, posCover []implicant, forms []Form, sopTable *coverTable, posTable *coverTable, optimal bool, inLabels InputLabels, outLabels OutputLabels) Result {
+	r := Result{
+		NumInputs: solver.implicantDisplayWidth,
+		Cost:      solver.formCost(sopCover, forms, SumOfProducts) + solver.formCost(posCover, forms, ProductOfSums),
+		Optimal:   optimal,
+		inLabels:  inLabels,
+		outLabels: outLabels,
+		minterms:  solver.minterms,
+		dontCares: solver.dontCares,
+		offSets:   solver.offSets,
+	}
 
-	for output := 0; output < solver.m_implicantTable.nOutputs; output++ {
-		logicalEquation := outLabels.Str(output) + " = "
+	// the implicants of each output are listed in canonical order
+	sopCover = append([]implicant{}, sopCover...)
+	slices.SortFunc(sopCover, implicant.less)
+	posCover = append([]implicant{}, posCover...)
+	slices.SortFunc(posCover, implicant.less)
 
-		for i, prime := range implicants {
-			if (prime.tag>>output)&1 == 0 {
-				continue
+	for output, form := range forms {
+		cover, table := sopCover, sopTable
+		if form == ProductOfSums {
+			cover, table = posCover, posTable
+		}
+
+		o := OutputResult{Form: form, Implicants: []Implicant{}, Essential: []bool{}}
+		for _, im := range cover {
+			if im.tag.bit(output) {
+				o.Implicants = append(o.Implicants, Implicant{im, solver.implicantDisplayWidth})
+				o.Essential = append(o.Essential, solver.engine == QuineMcCluskey && table.isEssential(im, output))
 			}
+		}
+		o.Cost, o.Literals = solver.outputCost(cover, output)
 
-			for j := 0; j < solver.implicantDisplayWidth; j++ {
-				bit := solver.implicantDisplayWidth - j - 1
-				if (prime.xMask>>bit)&1 == 0 {
-					logicalEquation += inLabels.Str(bit)
-					if (prime.literals >> (bit) & 1) == 0 {
-						logicalEquation += "'"
-					}
+		r.Outputs = append(r.Outputs, o)
+	}
 
-					if j != solver.implicantDisplayWidth-1 {
-						logicalEquation += "."
-					}
-				}
+	return r
+}
+
+// solve finds up to limit minimum cost covers of the LogicFunction, or all of
+// them if limit is 0, and returns a Result for each distinct solution. If a
+// cover fails verification, or the context is done before the covers are
+// found, a single Result holding the error is returned.
+func (solver *LogicFunction) solve(ctx context.Context, inLabels InputLabels, outLabels OutputLabels, limit int) []Result {
+	if err := ctx.Err(); err != nil {
+		return []Result{{Err: err}}
+	}
+
+	if len(solver.minterms) == 0 {
+		return []Result{{Err: ErrNoOutputs}}
+	}
+
+	// maxterms are found by enumerating every term of the inputs, unless the
+	// off-set of every output is given
+	enumerated := slices.IndexFunc(solver.offSets, func(offSet []bitset) bool { return offSet == nil }) >= 0
+	if solver.form != SumOfProducts && solver.implicantDisplayWidth > maxtermInputLimit && enumerated {
+		return []Result{{Err: fmt.Errorf("%w: %d inputs exceeds the limit of %d for ProductOfSums and AutoForm", ErrTooManyInputs, solver.implicantDisplayWidth, maxtermInputLimit)}}
+	}
+
+	sopCovers, posCovers := [][]implicant{nil}, [][]implicant{nil}
+	sopOptimal, posOptimal := true, true
+	var posTable coverTable
+
+	// the tables are consumed by solving, so are rebuilt for every call. The
+	// implicant table is not used where an off-set is given
+	solver.m_implicantTable.init()
+	if !anyOffSet(solver.offSets) {
+		for output := range solver.minterms {
+			solver.m_implicantTable.addOutput(solver.minterms[output], solver.dontCares[output])
+		}
+	}
+	solver.m_coverTable = coverTable{}
+
+	// progress is reported from the events passed to the observer
+	observer := solver.observer
+	if solver.progress != nil {
+		observer = &progressObserver{report: solver.progress, next: solver.observer}
+	}
+
+	// under AutoForm, covers which differ only in outputs rendered in the
+	// other form give the same solution, so every cover is found before the
+	// limit is applied to the solutions
+	coverLimit := limit
+	if solver.form == AutoForm && limit > 1 {
+		coverLimit = 0
+	}
+
+	if solver.form != ProductOfSums {
+		notify(observer, MinimizeStartedEvent{SumOfProducts})
+		sopCovers, sopOptimal = solver.minimize(ctx, observer, &solver.m_implicantTable, &solver.m_coverTable, solver.minterms, solver.dontCares, solver.offSets, coverLimit)
+		if err := ctx.Err(); err != nil {
+			return []Result{{Err: err}}
+		}
+
+		// verify that the found minimum cost covers are correct solutions
+		for _, cover := range sopCovers {
+			if !solver.verifyCover(cover, solver.minterms, solver.dontCares, solver.offSets) {
+				return []Result{{Err: ErrVerificationFailed}}
 			}
+		}
+	}
+
+	if solver.form != SumOfProducts {
+		notify(observer, MinimizeStartedEvent{ProductOfSums})
 
-			if logicalEquation[len(logicalEquation)-1:] == "." {
-				logicalEquation = logicalEquation[:len(logicalEquation)-1]
+		// minimize the maxterms of each output in a separate implicant table.
+		// Where the off-set of an output is given, the maxterms are its
+		// off-set, and its minterms are the off-set of its complement
+		maxterms := solver.maxterms()
+		posOffSets := make([][]bitset, len(maxterms))
+		var iTable implicantTable
+		iTable.init()
+		for output := range maxterms {
+			if solver.offSets[output] != nil {
+				posOffSets[output] = solver.minterms[output]
 			}
+			notify(observer, solver.outputAddedEvent(output, maxterms[output], solver.dontCares[output], posOffSets[output]))
+		}
+		if !anyOffSet(posOffSets) {
+			for output := range maxterms {
+				iTable.addOutput(maxterms[output], solver.dontCares[output])
+			}
+		}
+		posCovers, posOptimal = solver.minimize(ctx, observer, &iTable, &posTable, maxterms, solver.dontCares, posOffSets, coverLimit)
+		if err := ctx.Err(); err != nil {
+			return []Result{{Err: err}}
+		}
 
-			if i != len(implicants)-1 {
-				logicalEquation += " + "
+		// verify that the found minimum cost covers are correct solutions
+		for _, cover := range posCovers {
+			if !solver.verifyCover(cover, maxterms, solver.dontCares, posOffSets) {
+				return []Result{{Err: ErrVerificationFailed}}
 			}
 		}
+	}
 
-		if logicalEquation[len(logicalEquation)-3:] == " + " {
-			logicalEquation = logicalEquation[:len(logicalEquation)-3]
+	// select the form of each output, preferring the sum of products where
+	// both forms have the same cost
+	forms := make([]Form, len(solver.minterms))
+	for output := range forms {
+		forms[output] = solver.form
+		if solver.form == AutoForm {
+			sopCost, sopLiterals := solver.outputCost(sopCovers[0], output)
+			posCost, posLiterals := solver.outputCost(posCovers[0], output)
+			forms[output] = SumOfProducts
+			if posCost < sopCost || (posCost == sopCost && posLiterals < sopLiterals) {
+				forms[output] = ProductOfSums
+			}
 		}
+	}
+
+	// covers which differ only in outputs rendered in the other form produce
+	// the same solution
+	results := []Result{}
+	solutions := []string{}
+	for _, sopCover := range sopCovers {
+		for _, posCover := range posCovers {
+			if limit > 0 && len(results) == limit {
+				break
+			}
 
-		outputEquations += logicalEquation + "\n"
+			r := solver.result(sopCover, posCover, forms, &solver.m_coverTable, &posTable, sopOptimal && posOptimal, inLabels, outLabels)
+			if solution := r.String(); !slices.Contains(solutions, solution) {
+				results = append(results, r)
+				solutions = append(solutions, solution)
+			}
+		}
 	}
 
-	return outputEquations
+	solver.coverOptimal = results[0].Optimal
+	solver.coverCost = results[0].Cost
+
+	return results
 }
 
 // GetMinimumCostCover will solve the LogicFunction for a minimum cost cover
-// and return a string representation of the solution with outputs and input
-// bits printed using the labels described in InputLabels and OutputLabels.
-func (solver *LogicFunction) GetMinimumCostCover(inLabels InputLabels, outLabels OutputLabels) string {
-	// reduce the implicant table to identify prime implicants
-	primeImplicants := solver.m_implicantTable.reduce(solver.implicantDisplayWidth, solver.mintermDisplayWidth, solver.printoutsEnabled)
+// and return it as a Result. Its String method renders the solution with
+// outputs and input bits printed using the labels described in InputLabels
+// and OutputLabels. If a correct cover cannot be found, the Result's Err is
+// set.
+//
+// The Result is deterministic, so solving the same outputs with the same
+// settings always gives the same Result. Primes are considered in canonical
+// order, comparing the DontCareMask and then the Literals of each as binary
+// numbers, and ties between covers of equal cost are broken as follows:
+//
+//   - the cover with the fewest literals is preferred;
+//   - GreedySolver picks the cheapest of the primes covering the most rows
+//     per unit of cost, and then the first in canonical order;
+//   - exact CoverSolvers return the first minimum cost cover found when
+//     branching on primes in canonical order;
+//   - AutoForm prefers SumOfProducts where both forms are equal.
+func (solver *LogicFunction) GetMinimumCostCover(inLabels InputLabels, outLabels OutputLabels) Result {
+	return solver.solve(context.Background(), inLabels, outLabels, 1)[0]
+}
 
-	// solve the cover table of prime implicants for a minimum cost cover
-	solver.m_coverTable.build(solver.minterms, primeImplicants)
-	minimumCostCover := solver.m_coverTable.getMinimumCostCover(solver.implicantDisplayWidth, solver.mintermDisplayWidth, solver.printoutsEnabled)
+// GetMinimumCostCoverContext is GetMinimumCostCover, stopping early if the
+// passed context is done, in which case the Result's Err is set to the
+// context's error.
+func (solver *LogicFunction) GetMinimumCostCoverContext(ctx context.Context, inLabels InputLabels, outLabels OutputLabels) Result {
+	return solver.solve(ctx, inLabels, outLabels, 1)[0]
+}
 
-	// verify that the found minimum cost cover is a correct solution
-	if !solver.verifyCover(minimumCostCover) {
-		return "failed to yeild a correct solution"
-	}
+// GetAllMinimumCostCovers will solve the LogicFunction for every minimum cost
+// cover, up to limit covers or all of them if limit is 0, and return a Result
+// for each distinct solution. Covers of equal cost are only considered to be
+// minimal if they also have the fewest literals. More than one cover is only
+// found by the QuineMcCluskey engine using a CoverSolver which implements
+// CoverEnumerator, such as PetrickSolver or BranchAndBoundSolver. If a correct
+// solution cannot be found, a single Result with its Err set is returned.
+func (solver *LogicFunction) GetAllMinimumCostCovers(inLabels InputLabels, outLabels OutputLabels, limit int) []Result {
+	return solver.solve(context.Background(), inLabels, outLabels, limit)
+}
 
-	// return a string representation of yhe minimum cost cover
-	return solver.stringifyLogicFunction(minimumCostCover, inLabels, outLabels)
+// GetAllMinimumCostCoversContext is GetAllMinimumCostCovers, stopping early if
+// the passed context is done, in which case a single Result is returned with
+// its Err set to the context's error.
+func (solver *LogicFunction) GetAllMinimumCostCoversContext(ctx context.Context, inLabels InputLabels, outLabels OutputLabels, limit int) []Result {
+	return solver.solve(ctx, inLabels, outLabels, limit)
 }
@@ -0,0 +1,258 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestForms(t *testing.T) {
+	tests := []struct {
+		name    string
+		inputs  int
+		outputs []testOutput
+		form    Form
+		want    string
+	}{
+		{"sum of products", 2, []testOutput{{[]uint64{0, 1, 2}, nil}}, SumOfProducts, "f0 = x1' + x0'\n"},
+		{"product of sums", 2, []testOutput{{[]uint64{0, 1, 2}, nil}}, ProductOfSums, "f0 = (x1' + x0')\n"},
+		{"product of sums with don't cares", 3, []testOutput{{[]uint64{0, 1, 2}, []uint64{5, 6}}}, ProductOfSums, "f0 = x2'.(x1' + x0')\n"},
+		{"constant 0", 2, []testOutput{{nil, nil}}, ProductOfSums, "f0 = 0\n"},
+		{"automatic form", 3, []testOutput{{[]uint64{0, 1, 2, 3, 4, 5, 6}, nil}, {[]uint64{7}, nil}}, AutoForm, "f0 = (x2' + x1' + x0')\nf1 = x2.x1.x0\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newTestFunction(t, test.inputs, test.outputs)
+			f.SetForm(test.form)
+			r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+			checkResult(t, r, test.outputs)
+			if got := r.String(); got != test.want {
+				t.Errorf("GetMinimumCostCover() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 30; i++ {
+		inputs := 2 + rng.Intn(4)
+		outputs := randomOutputs(rng, inputs, 1+rng.Intn(3))
+		form := []Form{SumOfProducts, ProductOfSums, AutoForm}[i%3]
+
+		t.Run(fmt.Sprintf("%d inputs %d outputs form %d", inputs, len(outputs), form), func(t *testing.T) {
+			f := newTestFunction(t, inputs, outputs)
+			f.SetForm(form)
+			f.SetCoverSolver(BranchAndBoundSolver{})
+			checkResult(t, f.GetMinimumCostCover(InputLabels{}, OutputLabels{}), outputs)
+		})
+	}
+}
+
+func TestWideFunction(t *testing.T) {
+	zeros := strings.Repeat("0", 68)
+	minterms := []string{"1" + zeros + "1", "0" + zeros + "1", "1" + zeros + "0"}
+
+	for _, engine := range []Engine{QuineMcCluskey, Espresso} {
+		var f LogicFunction
+		f.Init(false)
+		f.SetEngine(engine)
+		if err := f.AddOutputTerms(minterms, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+		if r.Err != nil {
+			t.Fatalf("engine %d: Result.Err = %v", engine, r.Err)
+		}
+		got := []string{}
+		for _, im := range r.Outputs[0].Implicants {
+			got = append(got, im.String())
+		}
+		if want := []string{"1" + zeros + "x", "x" + zeros + "1"}; r.NumInputs != 70 || !slices.Equal(got, want) {
+			t.Errorf("engine %d: cover of %d inputs is %v, want 70 inputs %v", engine, r.NumInputs, got, want)
+		}
+	}
+}
+
+func TestManyOutputs(t *testing.T) {
+	outputs := randomOutputs(rand.New(rand.NewSource(1)), 3, 70)
+
+	for _, engine := range []Engine{QuineMcCluskey, Espresso} {
+		f := newTestFunction(t, 3, outputs)
+		f.SetEngine(engine)
+		r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+		checkResult(t, r, outputs)
+
+		// the primes of the 8 terms of 3 inputs are shared between outputs
+		shared := false
+		for _, output := range r.Outputs {
+			for _, im := range output.Implicants {
+				if outputs := im.Outputs(); outputs[len(outputs)-1] >= 64 && len(outputs) > 1 {
+					shared = true
+				}
+			}
+		}
+		if !shared {
+			t.Errorf("engine %d: no implicant is shared with an output above 63", engine)
+		}
+	}
+}
+
+func TestGetAllMinimumCostCovers(t *testing.T) {
+	// the cyclic function of minterms 0, 1, 2, 5, 6 and 7 has two minimum
+	// covers
+	f := newTestFunction(t, 3, []testOutput{{[]uint64{0, 1, 2, 5, 6, 7}, nil}})
+	f.SetCoverSolver(PetrickSolver{})
+	got := []string{}
+	for _, r := range f.GetAllMinimumCostCovers(InputLabels{}, OutputLabels{}, 0) {
+		got = append(got, r.String())
+	}
+	want := []string{"f0 = x2'.x1' + x2.x0 + x1.x0'\n", "f0 = x2.x1 + x2'.x0' + x1'.x0\n"}
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("GetAllMinimumCostCovers() = %q, want %q", got, want)
+	}
+}
+
+func TestGetAllMinimumCostCoversLimit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 60; i++ {
+		inputs := 3 + rng.Intn(2)
+		outputs := randomOutputs(rng, inputs, 1+rng.Intn(3))
+		solver := []CoverSolver{PetrickSolver{}, BranchAndBoundSolver{}}[i%2]
+		form := []Form{SumOfProducts, AutoForm}[i/2%2]
+
+		t.Run(fmt.Sprintf("%d inputs %d outputs %T form %d", inputs, len(outputs), solver, form), func(t *testing.T) {
+			solve := func(limit int) []string {
+				f := newTestFunction(t, inputs, outputs)
+				f.SetCoverSolver(solver)
+				f.SetForm(form)
+				solutions := []string{}
+				for _, r := range f.GetAllMinimumCostCovers(InputLabels{}, OutputLabels{}, limit) {
+					checkResult(t, r, outputs)
+					solutions = append(solutions, r.String())
+				}
+				return solutions
+			}
+
+			all := solve(0)
+			for limit := 1; limit <= len(all)+1; limit++ {
+				want := all
+				if limit < len(all) {
+					want = all[:limit]
+				}
+				if got := solve(limit); len(got) != len(want) {
+					t.Errorf("limit %d gives %d solutions, want %d of %d", limit, len(got), len(want), len(all))
+				}
+			}
+		})
+	}
+}
+
+func TestConstantFunctions(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []testOutput
+		want    string
+	}{
+		{"no terms", []testOutput{{nil, nil}}, "f0 = 0\n"},
+		{"no terms of any output", []testOutput{{nil, nil}, {nil, nil}}, "f0 = 0\nf1 = 0\n"},
+		{"only don't cares", []testOutput{{nil, []uint64{1, 6}}}, "f0 = 0\n"},
+		{"every term", []testOutput{{[]uint64{0, 1, 2, 3, 4, 5, 6, 7}, nil}}, "f0 = 1\n"},
+		{"every term or don't care", []testOutput{{[]uint64{0, 1, 2, 3}, []uint64{4, 5, 6, 7}}}, "f0 = 1\n"},
+	}
+
+	for _, test := range tests {
+		for _, engine := range []Engine{QuineMcCluskey, Espresso} {
+			for _, form := range []Form{SumOfProducts, ProductOfSums, AutoForm} {
+				t.Run(fmt.Sprintf("%s engine %d form %d", test.name, engine, form), func(t *testing.T) {
+					f := newTestFunction(t, 3, test.outputs)
+					f.SetEngine(engine)
+					f.SetForm(form)
+					r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+					checkResult(t, r, test.outputs)
+					if got := r.String(); got != test.want {
+						t.Errorf("GetMinimumCostCover() = %q, want %q", got, test.want)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestSetNumInputs(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs []int
+		want   string
+	}{
+		{"inferred", nil, "f0 = x1'.x0\n"},
+		{"declared", []int{4}, "f0 = x3'.x2'.x1'.x0\n"},
+		{"restored", []int{4, 0}, "f0 = x1'.x0\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var f LogicFunction
+			f.Init(false)
+			if err := f.AddOutput([]uint64{1}, []uint64{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.AddOutput([]uint64{2}, []uint64{}); err != nil {
+				t.Fatal(err)
+			}
+			for _, n := range test.inputs {
+				if err := f.SetNumInputs(n); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+			if got := strings.SplitAfter(r.String(), "\n")[0]; got != test.want {
+				t.Errorf("GetMinimumCostCover() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10; i++ {
+		outputs := randomOutputs(rng, 5, 3)
+		engine := []Engine{QuineMcCluskey, Espresso}[i%2]
+
+		// the result and trace do not depend on the order of the terms
+		solve := func() (string, string) {
+			shuffled := []testOutput{}
+			for _, output := range outputs {
+				minterms := append([]uint64{}, output.minterms...)
+				dontCares := append([]uint64{}, output.dontCares...)
+				rng.Shuffle(len(minterms), func(i, j int) { minterms[i], minterms[j] = minterms[j], minterms[i] })
+				rng.Shuffle(len(dontCares), func(i, j int) { dontCares[i], dontCares[j] = dontCares[j], dontCares[i] })
+				shuffled = append(shuffled, testOutput{minterms, dontCares})
+			}
+
+			var trace strings.Builder
+			f := newTestFunction(t, 5, shuffled)
+			f.SetEngine(engine)
+			f.SetObserver(TextObserver{&trace})
+			return f.GetMinimumCostCover(InputLabels{}, OutputLabels{}).String(), trace.String()
+		}
+
+		want, wantTrace := solve()
+		for j := 0; j < 5; j++ {
+			if got, trace := solve(); got != want || trace != wantTrace {
+				t.Fatalf("engine %d: solutions differ:\n%s\n%s", engine, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package quinemccluskey
+
+// Observer receives an Event for each step taken to minimize a LogicFunction,
+// allowing the steps to be traced. Events are delivered synchronously, in the
+// order the steps are taken, and must not be modified by the Observer.
+type Observer interface {
+	Observe(event Event)
+}
+
+// Event is a step taken to minimize a LogicFunction. It is one of
+// OutputAddedEvent, MinimizeStartedEvent, ColumnGeneratedEvent,
+// PrimesFoundEvent, CoverTableBuiltEvent, EssentialPrimesRemovedEvent,
+// ImplicantSelectedEvent, MinimumCoverFoundEvent or EspressoStepEvent.
+type Event interface {
+	event()
+}
+
+// OutputAddedEvent is sent when an output is added to be minimized. When the
+// ProductOfSums form is minimized, it is also sent for the maxterms of each
+// output.
+type OutputAddedEvent struct {
+	// Output is the index of the output.
+	Output int
+	// Minterms and DontCares list the terms of the output as strings of '1's
+	// and '0's, with the most significant input first.
+	Minterms  []string
+	DontCares []string
+	// OffSet lists the terms of the off-set of the output, if it was given,
+	// in which case every term in none of the lists is a don't care.
+	// Otherwise it is nil, and every term in neither of the other lists is
+	// in the off-set.
+	OffSet []string
+}
+
+// MinimizeStartedEvent is sent before the outputs are minimized in a form.
+type MinimizeStartedEvent struct {
+	Form Form
+}
+
+// TableEntry is an implicant in a column of the implicant table.
+type TableEntry struct {
+	Implicant Implicant
+	// Checked reports whether the implicant has been combined into an
+	// implicant of a later column with the same outputs. Implicants which
+	// remain unchecked are prime.
+	Checked bool
+}
+
+// ColumnGeneratedEvent is sent by the QuineMcCluskey engine for the first
+// column of the implicant table, and for each column generated by combining
+// the implicants of the previous column. Where the off-set of an output is
+// given, no implicant table is built, as the primes are instead generated
+// from the complement of the off-set.
+type ColumnGeneratedEvent struct {
+	// Column is the index of the generated column.
+	Column int
+	// Columns holds every column of the table so far, each as a list of
+	// groups of entries, where group i holds the implicants with i '1'
+	// literals. Each group is in canonical order.
+	Columns [][][]TableEntry
+	// NumOutputs is the number of outputs in the table.
+	NumOutputs int
+}
+
+// PrimesFoundEvent is sent by the QuineMcCluskey engine once every prime
+// implicant of the function has been found. The primes are in canonical
+// order. Where the off-set of an output is given, only the primes covering a
+// minterm are listed.
+type PrimesFoundEvent struct {
+	Primes []Implicant
+}
+
+// CoverTableBuiltEvent is sent by the QuineMcCluskey engine once the cover
+// table of prime implicants has been built.
+type CoverTableBuiltEvent struct {
+	Table CoverProblem
+}
+
+// EssentialPrime is a prime which is the only prime in the cover table
+// covering a minterm of an output.
+type EssentialPrime struct {
+	Implicant Implicant
+	// Output and Minterm identify the first minterm found which is covered
+	// by no other prime.
+	Output  int
+	Minterm string
+}
+
+// EssentialPrimesRemovedEvent is sent by the QuineMcCluskey engine once the
+// essential primes, and the minterms they cover, have been removed from the
+// cover table.
+type EssentialPrimesRemovedEvent struct {
+	// Essential lists each prime once for every output it is essential to.
+	Essential []EssentialPrime
+	// Table is the remaining cover table.
+	Table CoverProblem
+}
+
+// ImplicantSelectedEvent is sent by the QuineMcCluskey engine for each prime
+// selected by the CoverSolver, once it has been removed from the cover table.
+type ImplicantSelectedEvent struct {
+	Implicant Implicant
+	// Table is the remaining cover table.
+	Table CoverProblem
+}
+
+// MinimumCoverFoundEvent is sent by GetAllMinimumCostCovers for each minimum
+// cost cover found by the QuineMcCluskey engine.
+type MinimumCoverFoundEvent struct {
+	// Index is the position of the cover in the list of minimum cost covers.
+	Index      int
+	Cover      []Implicant
+	NumOutputs int
+}
+
+// EspressoStep identifies a step of the Espresso engine.
+type EspressoStep int
+
+const (
+	// EspressoInitialCover is the expanded and irredundant initial cover.
+	EspressoInitialCover EspressoStep = iota
+	// EspressoReduceExpandIrredundant is an improvement found by iterating
+	// the REDUCE, EXPAND and IRREDUNDANT steps.
+	EspressoReduceExpandIrredundant
+	// EspressoLastGasp is an improvement found by the LAST_GASP step.
+	EspressoLastGasp
+)
+
+// EspressoStepEvent is sent by the Espresso engine for its initial cover, and
+// each time a step improves the cover.
+type EspressoStepEvent struct {
+	Step       EspressoStep
+	Cover      []Implicant
+	NumOutputs int
+}
+
+func (OutputAddedEvent) event()            {}
+func (MinimizeStartedEvent) event()        {}
+func (ColumnGeneratedEvent) event()        {}
+func (PrimesFoundEvent) event()            {}
+func (CoverTableBuiltEvent) event()        {}
+func (EssentialPrimesRemovedEvent) event() {}
+func (ImplicantSelectedEvent) event()      {}
+func (MinimumCoverFoundEvent) event()      {}
+func (EspressoStepEvent) event()           {}
+
+// notify passes the event to the observer, if there is one.
+func notify(observer Observer, event Event) {
+	if observer != nil {
+		observer.Observe(event)
+	}
+}
+
+// exportImplicants returns the passed implicants as a list of Implicants with
+// the passed number of inputs.
+func exportImplicants(implicants []implicant, width int) []Implicant {
+	exported := []Implicant{}
+	for _, im := range implicants {
+		exported = append(exported, Implicant{im, width})
+	}
+	return exported
+}
+
+// binaryTerms returns the passed terms as strings of '1's and '0's with the
+// most significant of the passed number of inputs first.
+func binaryTerms(terms []bitset, width int) []string {
+	s := []string{}
+	for _, term := range terms {
+		s = append(s, term.binary(width))
+	}
+	return s
+}
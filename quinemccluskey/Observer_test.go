@@ -0,0 +1,83 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordingObserver records the events it is passed.
+type recordingObserver struct {
+	events []Event
+}
+
+func (o *recordingObserver) Observe(event Event) {
+	o.events = append(o.events, event)
+}
+
+// names returns the type names of the recorded events, without the package
+// name and the "Event" suffix.
+func (o *recordingObserver) names() string {
+	names := []string{}
+	for _, event := range o.events {
+		name := strings.TrimSuffix(fmt.Sprintf("%T", event), "Event")
+		names = append(names, strings.TrimPrefix(name, "quinemccluskey."))
+	}
+	return strings.Join(names, " ")
+}
+
+func TestObserverEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine Engine
+		form   Form
+		limit  int
+		want   string
+	}{
+		{"Quine-McCluskey", QuineMcCluskey, SumOfProducts, 1,
+			"OutputAdded MinimizeStarted ColumnGenerated ColumnGenerated PrimesFound CoverTableBuilt EssentialPrimesRemoved ImplicantSelected ImplicantSelected ImplicantSelected"},
+		{"all covers", QuineMcCluskey, SumOfProducts, 0,
+			"OutputAdded MinimizeStarted ColumnGenerated ColumnGenerated PrimesFound CoverTableBuilt EssentialPrimesRemoved MinimumCoverFound MinimumCoverFound"},
+		{"product of sums", QuineMcCluskey, ProductOfSums, 1,
+			"OutputAdded MinimizeStarted OutputAdded ColumnGenerated PrimesFound CoverTableBuilt EssentialPrimesRemoved"},
+		{"Espresso", Espresso, SumOfProducts, 1,
+			"OutputAdded MinimizeStarted EspressoStep EspressoStep"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var observer recordingObserver
+			var f LogicFunction
+			f.Init(false)
+			f.SetObserver(&observer)
+			f.SetEngine(test.engine)
+			f.SetForm(test.form)
+			f.SetCoverSolver(PetrickSolver{})
+			if err := f.AddOutput([]uint64{0, 1, 2, 5, 6, 7}, nil); err != nil {
+				t.Fatal(err)
+			}
+			f.GetAllMinimumCostCovers(InputLabels{}, OutputLabels{}, test.limit)
+
+			if got := observer.names(); got != test.want {
+				t.Errorf("events are %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTextObserver(t *testing.T) {
+	var b strings.Builder
+	var f LogicFunction
+	f.Init(false)
+	f.SetObserver(TextObserver{&b})
+	if err := f.AddOutput([]uint64{0, 1, 2, 5, 6, 7}, []uint64{3}); err != nil {
+		t.Fatal(err)
+	}
+	f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+
+	for _, want := range []string{"FUNCTION_ADDED: S(0, 1, 2, 5, 6, 7)", "D(3)", "0xx"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("TextObserver output does not contain %q:\n%s", want, b.String())
+		}
+	}
+}
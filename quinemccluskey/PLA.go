@@ -0,0 +1,265 @@
+package quinemccluskey
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// pla keywords which change the meaning of the rows, and are not supported
+var unsupportedPLAKeywords = []string{".pair", ".mv", ".kiss", ".symbolic"}
+
+// ReadPLA reads a function in the Berkeley PLA format used by Espresso and
+// other logic synthesis tools from r. The .ilb and .ob keywords give the
+// labels of the inputs, with the most significant first, and of the outputs.
+// Rows may be of type f, fd, fr or fdr, as declared by the .type keyword,
+// with fd assumed if it is not declared. The inputs of each row are a cube of
+// '1's, '0's and '-'s, which is expanded into the minterms it contains. An
+// output of '1' adds the cube to the on-set of that output, for types fd and
+// fdr, an output of '-' adds it to the don't care set, and for types fr and
+// fdr, an output of '0' adds it to the off-set. Terms in the don't care set of
+// an output are treated as don't cares. For types fr and fdr, the off-set of
+// each output is given, and every term in neither its on-set nor its off-set
+// is a don't care. An output declared with a '0' in the .phase line is the
+// complement of the function given by the rows, as written by WritePLA for
+// the ProductOfSums form. If the PLA is malformed, ErrInvalidPLA is returned,
+// if a term is in both the on-set and the off-set of an output,
+// ErrConflictingTerms is returned, and if a cube has too many '-'s to be
+// expanded, or an output of type f or fd of too many inputs is complemented,
+// ErrTooManyInputs is returned.
+func ReadPLA(r io.Reader) (Specification, error) {
+	pla := Specification{NumInputs: -1, NumOutputs: -1}
+	plaType, phase := "fd", ""
+	onSets, dontCareSets, offSets := []map[string]bool{}, []map[string]bool{}, []map[string]bool{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if strings.HasPrefix(fields[0], ".") {
+			switch keyword := fields[0]; keyword {
+			case ".i", ".o":
+				// a constant function has no inputs
+				n, err := strconv.Atoi(strings.Join(fields[1:], " "))
+				if err != nil || n < 0 || n == 0 && keyword == ".o" {
+					return Specification{}, fmt.Errorf("%w: line %d: invalid %s", ErrInvalidPLA, line, keyword)
+				}
+				if keyword == ".i" {
+					pla.NumInputs = n
+				} else {
+					pla.NumOutputs = n
+				}
+			case ".ilb":
+				pla.InputLabels = fields[1:]
+			case ".ob":
+				pla.OutputLabels = fields[1:]
+			case ".type":
+				if len(fields) != 2 || (fields[1] != "f" && fields[1] != "fd" && fields[1] != "fr" && fields[1] != "fdr") {
+					return Specification{}, fmt.Errorf("%w: line %d: unsupported .type %s", ErrInvalidPLA, line, strings.Join(fields[1:], " "))
+				}
+				plaType = fields[1]
+			case ".phase":
+				if len(fields) != 2 || strings.Trim(fields[1], "01") != "" {
+					return Specification{}, fmt.Errorf("%w: line %d: invalid .phase", ErrInvalidPLA, line)
+				}
+				phase = fields[1]
+			case ".e", ".end":
+				return finishPLA(pla, plaType, phase, onSets, dontCareSets, offSets)
+			default:
+				for _, unsupported := range unsupportedPLAKeywords {
+					if keyword == unsupported {
+						return Specification{}, fmt.Errorf("%w: line %d: unsupported keyword %s", ErrInvalidPLA, line, keyword)
+					}
+				}
+			}
+			continue
+		}
+
+		// a row is a cube of the inputs followed by a value for each output
+		if pla.NumInputs < 0 || pla.NumOutputs < 0 {
+			return Specification{}, fmt.Errorf("%w: line %d: row before .i and .o", ErrInvalidPLA, line)
+		}
+		for len(onSets) < pla.NumOutputs {
+			onSets = append(onSets, map[string]bool{})
+			dontCareSets = append(dontCareSets, map[string]bool{})
+			offSets = append(offSets, map[string]bool{})
+		}
+
+		row := strings.ReplaceAll(strings.Join(fields, ""), "|", "")
+		if len(row) != pla.NumInputs+pla.NumOutputs {
+			return Specification{}, fmt.Errorf("%w: line %d: expected %d inputs and %d outputs", ErrInvalidPLA, line, pla.NumInputs, pla.NumOutputs)
+		}
+
+		cube := row[:pla.NumInputs]
+		if i := strings.IndexFunc(cube, func(r rune) bool { return !strings.ContainsRune("01-2", r) }); i >= 0 {
+			return Specification{}, fmt.Errorf("%w: line %d: invalid input %q", ErrInvalidPLA, line, cube[i])
+		}
+		terms, err := expandCube(strings.ReplaceAll(cube, "2", "-"))
+		if err != nil {
+			return Specification{}, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		for o, value := range row[pla.NumInputs:] {
+			var set map[string]bool
+			switch value {
+			case '1', '4':
+				set = onSets[o]
+			case '-', '2':
+				if strings.Contains(plaType, "d") {
+					set = dontCareSets[o]
+				}
+			case '0', '3':
+				if strings.Contains(plaType, "r") {
+					set = offSets[o]
+				}
+			case '~':
+			default:
+				return Specification{}, fmt.Errorf("%w: line %d: invalid output %q", ErrInvalidPLA, line, value)
+			}
+
+			for _, term := range terms {
+				if set != nil {
+					set[term] = true
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Specification{}, err
+	}
+
+	return finishPLA(pla, plaType, phase, onSets, dontCareSets, offSets)
+}
+
+// finishPLA checks the declarations of a PLA read by ReadPLA, complements the
+// outputs of phase '0', and fills its minterms, don't cares and off-sets from
+// the on-set, don't care set and off-set of each output, the off-sets only
+// being given for types fr and fdr.
+func finishPLA(pla Specification, plaType string, phase string, onSets []map[string]bool, dontCareSets []map[string]bool, offSets []map[string]bool) (Specification, error) {
+	if pla.NumInputs < 0 || pla.NumOutputs < 0 {
+		return Specification{}, fmt.Errorf("%w: missing .i or .o", ErrInvalidPLA)
+	}
+	if len(pla.InputLabels) > 0 && len(pla.InputLabels) != pla.NumInputs {
+		return Specification{}, fmt.Errorf("%w: .ilb has %d labels for %d inputs", ErrInvalidPLA, len(pla.InputLabels), pla.NumInputs)
+	}
+	if len(pla.OutputLabels) > 0 && len(pla.OutputLabels) != pla.NumOutputs {
+		return Specification{}, fmt.Errorf("%w: .ob has %d labels for %d outputs", ErrInvalidPLA, len(pla.OutputLabels), pla.NumOutputs)
+	}
+	if phase != "" && len(phase) != pla.NumOutputs {
+		return Specification{}, fmt.Errorf("%w: .phase has %d values for %d outputs", ErrInvalidPLA, len(phase), pla.NumOutputs)
+	}
+
+	for len(onSets) < pla.NumOutputs {
+		onSets = append(onSets, map[string]bool{})
+		dontCareSets = append(dontCareSets, map[string]bool{})
+		offSets = append(offSets, map[string]bool{})
+	}
+
+	// the on-set of a complemented output is the off-set of the rows, which
+	// for types f and fd is every term in neither their on-set nor don't
+	// care set
+	for o := 0; o < len(phase); o++ {
+		switch {
+		case phase[o] == '1':
+		case strings.Contains(plaType, "r"):
+			onSets[o], offSets[o] = offSets[o], onSets[o]
+		case pla.NumInputs > maxtermInputLimit:
+			return Specification{}, fmt.Errorf("%w: %d inputs exceeds the limit of %d for a complemented output of type %s", ErrTooManyInputs, pla.NumInputs, maxtermInputLimit, plaType)
+		default:
+			complement := map[string]bool{}
+			for term := 0; term < 1<<pla.NumInputs; term++ {
+				s := bitset{word: uint64(term)}.binary(pla.NumInputs)
+				if !onSets[o][s] && !dontCareSets[o][s] {
+					complement[s] = true
+				}
+			}
+			onSets[o] = complement
+		}
+	}
+
+	if !strings.Contains(plaType, "r") {
+		pla.setTerms(onSets, dontCareSets, nil)
+		return pla, nil
+	}
+
+	for o := range onSets {
+		for term := range onSets[o] {
+			if offSets[o][term] && !dontCareSets[o][term] {
+				return Specification{}, fmt.Errorf("%w: %s is in both the on-set and the off-set of output %d", ErrConflictingTerms, term, o)
+			}
+		}
+	}
+
+	pla.setTerms(onSets, dontCareSets, offSets)
+	return pla, nil
+}
+
+// WritePLA writes the cover held by a Result to w in the Berkeley PLA format,
+// with a row for each distinct implicant giving a '1' for each output whose
+// equation includes it. The implicants of outputs in the ProductOfSums form
+// cover the complement of the output, which is declared with a '0' for that
+// output in a .phase line. If the Result's Err is set, it is returned.
+func WritePLA(w io.Writer, r Result) error {
+	if r.Err != nil {
+		return r.Err
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, ".i %d\n.o %d\n", r.NumInputs, len(r.Outputs))
+
+	inLabels := []string{}
+	for i := r.NumInputs - 1; i >= 0; i-- {
+		inLabels = append(inLabels, r.inLabels.Str(i))
+	}
+	fmt.Fprintf(&b, ".ilb %s\n", strings.Join(inLabels, " "))
+
+	outLabels := []string{}
+	phase := []byte{}
+	for o, output := range r.Outputs {
+		outLabels = append(outLabels, r.outLabels.Str(o))
+		if output.Form == ProductOfSums {
+			phase = append(phase, '0')
+		} else {
+			phase = append(phase, '1')
+		}
+	}
+	fmt.Fprintf(&b, ".ob %s\n", strings.Join(outLabels, " "))
+	if strings.Contains(string(phase), "0") {
+		fmt.Fprintf(&b, ".phase %s\n", phase)
+	}
+
+	// rows are listed in the order their implicants first appear
+	cubes := []string{}
+	rows := map[string][]byte{}
+	for o, output := range r.Outputs {
+		for _, im := range output.Implicants {
+			cube := strings.ReplaceAll(im.String(), "x", "-")
+			if _, ok := rows[cube]; !ok {
+				cubes = append(cubes, cube)
+				rows[cube] = []byte(strings.Repeat("0", len(r.Outputs)))
+			}
+			rows[cube][o] = '1'
+		}
+	}
+
+	fmt.Fprintf(&b, ".type f\n.p %d\n", len(cubes))
+	for _, cube := range cubes {
+		fmt.Fprintf(&b, "%s %s\n", cube, rows[cube])
+	}
+	fmt.Fprintf(&b, ".e\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
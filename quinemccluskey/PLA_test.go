@@ -0,0 +1,202 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReadPLA(t *testing.T) {
+	tests := []struct {
+		name string
+		pla  string
+		want Specification
+	}{
+		{"fd by default", ".i 2\n.o 2\n.ilb a b\n.ob y z\n1- 1-\n01 01\n.e\n", Specification{
+			NumInputs: 2, NumOutputs: 2, InputLabels: []string{"a", "b"}, OutputLabels: []string{"y", "z"},
+			Minterms: [][]string{{"10", "11"}, {"01"}}, DontCares: [][]string{{}, {"10", "11"}},
+		}},
+		{"type f", ".i 2\n.o 1\n.type f\n1- -\n11 1\n", Specification{
+			NumInputs: 2, NumOutputs: 1, Minterms: [][]string{{"11"}}, DontCares: [][]string{{}},
+		}},
+		{"on-set and don't care", ".i 1\n.o 1\n1 1\n1 -\n", Specification{
+			NumInputs: 1, NumOutputs: 1, Minterms: [][]string{{}}, DontCares: [][]string{{"1"}},
+		}},
+		{"type fr", ".i 2\n.o 1\n.type fr\n11 1\n0- 0\n", Specification{
+			NumInputs: 2, NumOutputs: 1, Minterms: [][]string{{"11"}}, DontCares: [][]string{{}},
+			OffSets: [][]string{{"00", "01"}},
+		}},
+		{"type fdr", ".i 2\n.o 2\n.type fdr\n11 1-\n00 01\n10 -0\n", Specification{
+			NumInputs: 2, NumOutputs: 2, Minterms: [][]string{{"11"}, {"00"}}, DontCares: [][]string{{}, {}},
+			OffSets: [][]string{{"00"}, {"10"}},
+		}},
+		{"phase of type f", ".i 2\n.o 2\n.phase 01\n.type f\n1- 11\n", Specification{
+			NumInputs: 2, NumOutputs: 2, Minterms: [][]string{{"00", "01"}, {"10", "11"}}, DontCares: [][]string{{}, {}},
+		}},
+		{"phase of type fd", ".i 2\n.o 1\n.phase 0\n11 1\n00 -\n", Specification{
+			NumInputs: 2, NumOutputs: 1, Minterms: [][]string{{"01", "10"}}, DontCares: [][]string{{"00"}},
+		}},
+		{"phase of type fr", ".i 1\n.o 1\n.type fr\n.phase 0\n1 1\n0 0\n", Specification{
+			NumInputs: 1, NumOutputs: 1, Minterms: [][]string{{"0"}}, DontCares: [][]string{{}},
+			OffSets: [][]string{{"1"}},
+		}},
+		{"comments and separators", ".i 2 # inputs\n.o 1\n\n2|0 4\n.e\n11 1\n", Specification{
+			NumInputs: 2, NumOutputs: 1, Minterms: [][]string{{"00", "10"}}, DontCares: [][]string{{}},
+		}},
+		{"no rows", ".i 2\n.o 2\n", Specification{
+			NumInputs: 2, NumOutputs: 2, Minterms: [][]string{{}, {}}, DontCares: [][]string{{}, {}},
+		}},
+		{"no inputs", ".i 0\n.o 2\n.phase 01\n 11\n", Specification{
+			NumInputs: 0, NumOutputs: 2, Minterms: [][]string{{}, {""}}, DontCares: [][]string{{}, {}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec, err := ReadPLA(strings.NewReader(test.pla))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(spec, test.want) {
+				t.Errorf("ReadPLA() = %+v, want %+v", spec, test.want)
+			}
+		})
+	}
+}
+
+func TestReadPLAErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		pla  string
+		want error
+	}{
+		{"missing .i", ".o 1\n", ErrInvalidPLA},
+		{"invalid .i", ".i x\n.o 1\n", ErrInvalidPLA},
+		{"negative .i", ".i -1\n.o 1\n", ErrInvalidPLA},
+		{"invalid .o", ".i 1\n.o 0\n", ErrInvalidPLA},
+		{"row before .i and .o", "1 1\n", ErrInvalidPLA},
+		{"row length", ".i 2\n.o 1\n1 1\n", ErrInvalidPLA},
+		{"invalid input", ".i 2\n.o 1\n1x 1\n", ErrInvalidPLA},
+		{"invalid output", ".i 1\n.o 1\n1 x\n", ErrInvalidPLA},
+		{"unsupported .type", ".type r\n", ErrInvalidPLA},
+		{"unsupported keyword", ".i 1\n.o 1\n.mv 2 0\n", ErrInvalidPLA},
+		{".ilb labels", ".i 2\n.o 1\n.ilb a\n", ErrInvalidPLA},
+		{".ob labels", ".i 2\n.o 1\n.ob y z\n", ErrInvalidPLA},
+		{"invalid .phase", ".i 1\n.o 1\n.phase 2\n", ErrInvalidPLA},
+		{".phase values", ".i 1\n.o 2\n.phase 0\n", ErrInvalidPLA},
+		{"on-set and off-set", ".i 1\n.o 1\n.type fr\n1 1\n- 0\n", ErrConflictingTerms},
+		{"cube too large", fmt.Sprintf(".i %d\n.o 1\n%s 1\n", maxtermInputLimit+1, strings.Repeat("-", maxtermInputLimit+1)), ErrTooManyInputs},
+		{"complement too large", fmt.Sprintf(".i %d\n.o 1\n.phase 0\n", maxtermInputLimit+1), ErrTooManyInputs},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ReadPLA(strings.NewReader(test.pla)); !errors.Is(err, test.want) {
+				t.Errorf("ReadPLA() error = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestPLARoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 30; i++ {
+		inputs := 1 + rng.Intn(4)
+		outputs := randomOutputs(rng, inputs, 1+rng.Intn(3))
+		form := []Form{SumOfProducts, ProductOfSums, AutoForm}[i%3]
+
+		t.Run(fmt.Sprintf("%d inputs %d outputs form %d", inputs, len(outputs), form), func(t *testing.T) {
+			f := newTestFunction(t, inputs, outputs)
+			f.SetForm(form)
+			r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+			checkResult(t, r, outputs)
+
+			var b strings.Builder
+			if err := WritePLA(&b, r); err != nil {
+				t.Fatal(err)
+			}
+			spec, err := ReadPLA(strings.NewReader(b.String()))
+			if err != nil {
+				t.Fatalf("ReadPLA() = %v\n%s", err, b.String())
+			}
+
+			// the function read is the function of the cover
+			read := make([]testOutput, len(spec.Minterms))
+			for o := range spec.Minterms {
+				if len(spec.DontCares[o]) > 0 {
+					t.Errorf("output %d has don't cares %v", o, spec.DontCares[o])
+				}
+				for _, term := range spec.Minterms[o] {
+					minterm, err := strconv.ParseUint(term, 2, 64)
+					if err != nil {
+						t.Fatal(err)
+					}
+					read[o].minterms = append(read[o].minterms, minterm)
+				}
+			}
+			checkResult(t, r, read)
+		})
+	}
+}
+
+func TestPLAConstantRoundTrip(t *testing.T) {
+	// a constant function has no inputs, so is written with ".i 0"
+	for _, form := range []Form{SumOfProducts, ProductOfSums} {
+		var f LogicFunction
+		f.Init(false)
+		f.SetForm(form)
+		if err := f.AddOutputTerms([]string{""}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.AddOutputTerms(nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+
+		var b strings.Builder
+		if err := WritePLA(&b, r); err != nil {
+			t.Fatal(err)
+		}
+		spec, err := ReadPLA(strings.NewReader(b.String()))
+		if err != nil {
+			t.Fatalf("ReadPLA() = %v\n%s", err, b.String())
+		}
+
+		var g LogicFunction
+		g.Init(false)
+		g.SetForm(form)
+		if err := g.AddSpecification(spec); err != nil {
+			t.Fatal(err)
+		}
+		if got := g.GetMinimumCostCover(spec.Labels()); got.String() != r.String() {
+			t.Errorf("form %d: the function read is\n%s\nwant\n%s", form, got, r)
+		}
+	}
+}
+
+func TestAddSpecificationOffSet(t *testing.T) {
+	spec, err := ReadPLA(strings.NewReader(".i 3\n.o 1\n.type fr\n111 1\n000 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f LogicFunction
+	f.Init(false)
+	if err := f.AddSpecification(spec); err != nil {
+		t.Fatal(err)
+	}
+
+	// every term but 000 and 111 is a don't care, so a single literal covers
+	r := f.GetMinimumCostCover(spec.Labels())
+	if r.Err != nil {
+		t.Fatal(r.Err)
+	}
+	if implicants := r.Outputs[0].Implicants; len(implicants) != 1 || implicants[0].LiteralCount() != 1 {
+		t.Errorf("cover is %v, want a single literal", implicants)
+	}
+}
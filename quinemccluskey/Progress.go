@@ -0,0 +1,76 @@
+package quinemccluskey
+
+// Progress reports how far the minimization of a LogicFunction has got. It is
+// passed to the function set by SetProgressFunc after each step.
+type Progress struct {
+	// Form is the form being minimized.
+	Form Form
+	// Column is the index of the last column generated in the implicant
+	// table by the QuineMcCluskey engine, and Implicants is the number of
+	// implicants in that column. For the Espresso engine, Implicants is the
+	// number of cubes in the current cover.
+	Column     int
+	Implicants int
+	// Primes and Rows are the number of primes and minterm rows remaining in
+	// the cover table, once it has been built.
+	Primes int
+	Rows   int
+}
+
+// progressObserver is an Observer which reports a Progress for each step to a
+// progress function, passing each event on to the next Observer.
+type progressObserver struct {
+	progress Progress
+	report   func(Progress)
+	next     Observer
+}
+
+// Observe implements Observer.
+func (p *progressObserver) Observe(event Event) {
+	notify(p.next, event)
+
+	switch e := event.(type) {
+	case MinimizeStartedEvent:
+		p.progress = Progress{Form: e.Form}
+	case ColumnGeneratedEvent:
+		p.progress.Column = e.Column
+		p.progress.Implicants = 0
+		for _, group := range e.Columns[e.Column] {
+			p.progress.Implicants += len(group)
+		}
+	case CoverTableBuiltEvent:
+		p.progress.Primes, p.progress.Rows = len(e.Table.Primes), len(e.Table.Rows)
+	case EssentialPrimesRemovedEvent:
+		p.progress.Primes, p.progress.Rows = len(e.Table.Primes), len(e.Table.Rows)
+	case ImplicantSelectedEvent:
+		p.progress.Primes, p.progress.Rows = len(e.Table.Primes), len(e.Table.Rows)
+	case EspressoStepEvent:
+		p.progress.Implicants = len(e.Cover)
+	default:
+		return
+	}
+
+	p.report(p.progress)
+}
+
+// progressOnly returns the passed Observer as a progressObserver if it passes
+// events to no other Observer. The events of steps which are costly to build
+// are then not needed, and their counts are reported to it directly.
+func progressOnly(observer Observer) (*progressObserver, bool) {
+	p, ok := observer.(*progressObserver)
+	return p, ok && p.next == nil
+}
+
+// columnGenerated reports the Progress of a ColumnGeneratedEvent which was not
+// built.
+func (p *progressObserver) columnGenerated(column int, implicants int) {
+	p.progress.Column, p.progress.Implicants = column, implicants
+	p.report(p.progress)
+}
+
+// implicantSelected reports the Progress of an ImplicantSelectedEvent which
+// was not built.
+func (p *progressObserver) implicantSelected(primes int, rows int) {
+	p.progress.Primes, p.progress.Rows = primes, rows
+	p.report(p.progress)
+}
@@ -0,0 +1,92 @@
+package quinemccluskey
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestProgress(t *testing.T) {
+	f := newTestFunction(t, 3, []testOutput{{[]uint64{0, 1, 2, 5, 6, 7}, nil}})
+	f.SetCoverSolver(PetrickSolver{})
+	reports := []Progress{}
+	f.SetProgressFunc(func(p Progress) { reports = append(reports, p) })
+	if r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{}); r.Err != nil {
+		t.Fatal(r.Err)
+	}
+
+	// the start of the minimization and the columns of 6 minterms and 6
+	// implicants are followed by the cover table of 6 primes, which has no
+	// essential primes, and fewer rows for each selected prime
+	want := []Progress{
+		{SumOfProducts, 0, 0, 0, 0},
+		{SumOfProducts, 0, 6, 0, 0},
+		{SumOfProducts, 1, 6, 0, 0},
+		{SumOfProducts, 1, 6, 6, 6},
+		{SumOfProducts, 1, 6, 6, 6},
+	}
+	if len(reports) < len(want) {
+		t.Fatalf("progress reports are %v, want at least %v", reports, want)
+	}
+	for i := range want {
+		if reports[i] != want[i] {
+			t.Errorf("progress report %d is %+v, want %+v", i, reports[i], want[i])
+		}
+	}
+	if last := reports[len(reports)-1]; last.Rows != 0 {
+		t.Errorf("last progress report is %+v, want no rows", last)
+	}
+}
+
+func TestProgressWithoutObserver(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// the progress reported without an Observer, when the events of costly
+	// steps are not built, matches the progress reported with one
+	for i := 0; i < 20; i++ {
+		inputs := 2 + rng.Intn(4)
+		outputs := randomOutputs(rng, inputs, 1+rng.Intn(3))
+		form := []Form{SumOfProducts, ProductOfSums}[i%2]
+
+		reports := [2][]Progress{}
+		for j, observer := range []Observer{nil, &recordingObserver{}} {
+			f := newTestFunction(t, inputs, outputs)
+			f.SetForm(form)
+			f.SetObserver(observer)
+			f.SetProgressFunc(func(p Progress) { reports[j] = append(reports[j], p) })
+			if r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{}); r.Err != nil {
+				t.Fatal(r.Err)
+			}
+		}
+
+		if !slices.Equal(reports[0], reports[1]) {
+			t.Errorf("progress reports without an Observer are %v, want %v", reports[0], reports[1])
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	outputs := randomOutputs(rand.New(rand.NewSource(1)), 8, 2)
+
+	for _, engine := range []Engine{QuineMcCluskey, Espresso} {
+		// a solve is cancelled before it starts, or from the progress
+		// function once it has started
+		cancelled, cancel := context.WithCancel(context.Background())
+		cancel()
+		f := newTestFunction(t, 8, outputs)
+		f.SetEngine(engine)
+		if r := f.GetMinimumCostCoverContext(cancelled, InputLabels{}, OutputLabels{}); !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("engine %d: Result.Err before solving = %v, want %v", engine, r.Err, context.Canceled)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		f.SetProgressFunc(func(Progress) { cancel() })
+		results := f.GetAllMinimumCostCoversContext(ctx, InputLabels{}, OutputLabels{}, 0)
+		if len(results) != 1 || !errors.Is(results[0].Err, context.Canceled) {
+			t.Errorf("engine %d: results while solving = %v, want a single Result with Err %v", engine, results, context.Canceled)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package quinemccluskey
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ReadROM reads the contents of a ROM from r, as a list of data words in
+// hexadecimal, such as "3f 2a 00", in order of address from 0. Words may be
+// separated by white space or commas, and may have a "0x" prefix. The address
+// of the following words may be set by a word starting with '@', as read by
+// Verilog's $readmemh, or by a hexadecimal address followed by ':' at the
+// start of a line, as in a hex dump. A word of 'x's or '-'s skips an address.
+// Text following '#' or "//" on a line is ignored. Each bit of the data word
+// is an output of the Specification, and each address bit an input, labelled
+// "data[i]" and "addr[i]" with the most significant first. Addresses given no
+// word are don't cares of every output. If the contents are malformed, or an
+// address or word is too large for the passed widths, ErrInvalidROM is
+// returned, if an address is given two different words, ErrConflictingTerms
+// is returned, if addressWidth is more than maxtermInputLimit,
+// ErrTooManyInputs is returned, and if dataWidth is more than 64,
+// ErrTooManyOutputs is returned.
+func ReadROM(r io.Reader, addressWidth int, dataWidth int) (Specification, error) {
+	if addressWidth <= 0 || dataWidth <= 0 {
+		return Specification{}, fmt.Errorf("%w: %d address bits and %d data bits", ErrInvalidROM, addressWidth, dataWidth)
+	}
+	if dataWidth > 64 {
+		return Specification{}, fmt.Errorf("%w: %d data bits exceeds the limit of 64 for ROMs", ErrTooManyOutputs, dataWidth)
+	}
+	if addressWidth > maxtermInputLimit {
+		return Specification{}, fmt.Errorf("%w: %d address bits exceeds the limit of %d for ROMs", ErrTooManyInputs, addressWidth, maxtermInputLimit)
+	}
+
+	words := map[uint64]uint64{}
+	wordLines := map[uint64]int{}
+	address := uint64(0)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		for _, comment := range []string{"#", "//"} {
+			if i := strings.Index(text, comment); i >= 0 {
+				text = text[:i]
+			}
+		}
+
+		// a hex dump gives the address of each line
+		if i := strings.IndexByte(text, ':'); i >= 0 {
+			a, err := parseROMHex(text[:i])
+			if err != nil {
+				return Specification{}, fmt.Errorf("%w: line %d: invalid address %q", ErrInvalidROM, line, strings.TrimSpace(text[:i]))
+			}
+			address, text = a, text[i+1:]
+		}
+
+		fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+		for _, field := range fields {
+			if strings.HasPrefix(field, "@") {
+				a, err := parseROMHex(field[1:])
+				if err != nil {
+					return Specification{}, fmt.Errorf("%w: line %d: invalid address %q", ErrInvalidROM, line, field)
+				}
+				address = a
+				continue
+			}
+
+			if address>>addressWidth != 0 {
+				return Specification{}, fmt.Errorf("%w: line %d: address %x exceeds %d address bits", ErrInvalidROM, line, address, addressWidth)
+			}
+
+			if strings.Trim(field, "xX-") == "" {
+				address++
+				continue
+			}
+
+			word, err := parseROMHex(field)
+			if err != nil {
+				return Specification{}, fmt.Errorf("%w: line %d: invalid word %q", ErrInvalidROM, line, field)
+			}
+			if dataWidth < 64 && word>>dataWidth != 0 {
+				return Specification{}, fmt.Errorf("%w: line %d: word %s exceeds %d data bits", ErrInvalidROM, line, field, dataWidth)
+			}
+
+			if previous, ok := words[address]; ok && previous != word {
+				return Specification{}, fmt.Errorf("%w: line %d: address %x is given %x, and %x by line %d", ErrConflictingTerms, line, address, word, previous, wordLines[address])
+			}
+			words[address], wordLines[address] = word, line
+			address++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Specification{}, err
+	}
+
+	spec := Specification{NumInputs: addressWidth, NumOutputs: dataWidth}
+	for i := addressWidth - 1; i >= 0; i-- {
+		spec.InputLabels = append(spec.InputLabels, fmt.Sprintf("addr[%d]", i))
+	}
+	for i := dataWidth - 1; i >= 0; i-- {
+		spec.OutputLabels = append(spec.OutputLabels, fmt.Sprintf("data[%d]", i))
+	}
+
+	onSets, dontCareSets := []map[string]bool{}, []map[string]bool{}
+	for o := 0; o < dataWidth; o++ {
+		onSets = append(onSets, map[string]bool{})
+		dontCareSets = append(dontCareSets, map[string]bool{})
+	}
+
+	// output o is data bit dataWidth-1-o
+	for a := uint64(0); a < 1<<addressWidth; a++ {
+		term := newBitset(a).binary(addressWidth)
+		word, ok := words[a]
+		for o := 0; o < dataWidth; o++ {
+			if !ok {
+				dontCareSets[o][term] = true
+			} else if word>>(dataWidth-1-o)&1 == 1 {
+				onSets[o][term] = true
+			}
+		}
+	}
+
+	spec.setTerms(onSets, dontCareSets, nil)
+	return spec, nil
+}
+
+// parseROMHex parses a hexadecimal address or word, with an optional "0x"
+// prefix.
+func parseROMHex(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	return strconv.ParseUint(s, 16, 64)
+}
@@ -0,0 +1,104 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadROM(t *testing.T) {
+	labels := func(s string) []string { return strings.Fields(s) }
+
+	tests := []struct {
+		name    string
+		rom     string
+		address int
+		data    int
+		want    Specification
+	}{
+		{"words in order", "1 2 3 0", 2, 2, Specification{
+			NumInputs: 2, NumOutputs: 2, InputLabels: labels("addr[1] addr[0]"), OutputLabels: labels("data[1] data[0]"),
+			Minterms: [][]string{{"01", "10"}, {"00", "10"}}, DontCares: [][]string{{}, {}},
+		}},
+		{"missing words", "0x1, 0X1\n", 2, 1, Specification{
+			NumInputs: 2, NumOutputs: 1, InputLabels: labels("addr[1] addr[0]"), OutputLabels: labels("data[0]"),
+			Minterms: [][]string{{"00", "01"}}, DontCares: [][]string{{"10", "11"}},
+		}},
+		{"readmemh", "// header\n@2 1\n@0 0 # low\n", 2, 1, Specification{
+			NumInputs: 2, NumOutputs: 1, InputLabels: labels("addr[1] addr[0]"), OutputLabels: labels("data[0]"),
+			Minterms: [][]string{{"10"}}, DontCares: [][]string{{"01", "11"}},
+		}},
+		{"hex dump", "00: 1 0\n02: xx 1\n", 2, 1, Specification{
+			NumInputs: 2, NumOutputs: 1, InputLabels: labels("addr[1] addr[0]"), OutputLabels: labels("data[0]"),
+			Minterms: [][]string{{"00", "11"}}, DontCares: [][]string{{"10"}},
+		}},
+		{"repeated word", "@1 1\n@1 1\n", 1, 1, Specification{
+			NumInputs: 1, NumOutputs: 1, InputLabels: labels("addr[0]"), OutputLabels: labels("data[0]"),
+			Minterms: [][]string{{"1"}}, DontCares: [][]string{{"0"}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec, err := ReadROM(strings.NewReader(test.rom), test.address, test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(spec, test.want) {
+				t.Errorf("ReadROM() = %+v, want %+v", spec, test.want)
+			}
+		})
+	}
+}
+
+func TestReadROMErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		rom     string
+		address int
+		data    int
+		want    error
+	}{
+		{"no address bits", "0", 0, 1, ErrInvalidROM},
+		{"no data bits", "0", 1, 0, ErrInvalidROM},
+		{"too many data bits", "0", 1, 65, ErrTooManyOutputs},
+		{"too many address bits", "0", maxtermInputLimit + 1, 1, ErrTooManyInputs},
+		{"invalid word", "1 g", 1, 4, ErrInvalidROM},
+		{"invalid @ address", "@g 1", 1, 4, ErrInvalidROM},
+		{"invalid dump address", "g: 1", 1, 4, ErrInvalidROM},
+		{"word too large", "10", 1, 4, ErrInvalidROM},
+		{"address too large", "1 2 3", 1, 4, ErrInvalidROM},
+		{"@ address too large", "@2 1", 1, 4, ErrInvalidROM},
+		{"conflicting words", "@0 1\n@0 2\n", 1, 4, ErrConflictingTerms},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ReadROM(strings.NewReader(test.rom), test.address, test.data); !errors.Is(err, test.want) {
+				t.Errorf("ReadROM(%q, %d, %d) error = %v, want %v", test.rom, test.address, test.data, err, test.want)
+			}
+		})
+	}
+}
+
+func TestReadROMSixtyFourBits(t *testing.T) {
+	spec, err := ReadROM(strings.NewReader("ffffffffffffffff 8000000000000001"), 1, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first output is the most significant data bit
+	if spec.NumOutputs != 64 || spec.OutputLabels[0] != "data[63]" {
+		t.Fatalf("ReadROM() has %d outputs, the first labelled %s", spec.NumOutputs, spec.OutputLabels[0])
+	}
+	for o, minterms := range spec.Minterms {
+		want := []string{"0"}
+		if o == 0 || o == 63 {
+			want = []string{"0", "1"}
+		}
+		if !reflect.DeepEqual(minterms, want) {
+			t.Errorf("minterms of output %d = %v, want %v", o, minterms, want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package quinemccluskey
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// OutputResult is the equation found for a single output of a LogicFunction.
+type OutputResult struct {
+	// Form is the form in which the output's equation is rendered. For
+	// ProductOfSums, each implicant covers maxterms of the output, and is
+	// rendered as a sum of its complemented literals.
+	Form Form
+	// Implicants lists the terms of the output's equation, in canonical
+	// order. The outputs of each implicant include every output that shares
+	// the term.
+	Implicants []Implicant
+	// Essential reports, for each of Implicants, whether it is an essential
+	// prime of the output. Essential primes are only identified by the
+	// QuineMcCluskey engine.
+	Essential []bool
+	// Cost is the cost under the CostModel of the output's implicants,
+	// considering only this output.
+	Cost float64
+	// Literals is the total number of literals in the output's implicants.
+	Literals int
+}
+
+// Result is a cover of a LogicFunction found by GetMinimumCostCover.
+type Result struct {
+	// NumInputs is the number of inputs of the LogicFunction.
+	NumInputs int
+	// Outputs holds the equation of each output of the LogicFunction.
+	Outputs []OutputResult
+	// Cost is the cost of the cover under the CostModel, with implicants that
+	// are shared between outputs counted once.
+	Cost float64
+	// Optimal reports whether the cover is proven to be of minimum cost.
+	Optimal bool
+	// Err is set if a correct cover could not be found, in which case the
+	// other fields are not set.
+	Err error
+
+	inLabels  InputLabels
+	outLabels OutputLabels
+	// minterms, dontCares and offSets are the terms of each output of the
+	// LogicFunction, from which a test of the cover is generated.
+	minterms  [][]bitset
+	dontCares [][]bitset
+	offSets   [][]bitset
+}
+
+// expectedValues returns the value of each output of the function covered by
+// the Result for the terms given a value other than 0 by some output, or
+// which are in an off-set. The values of a term are a string with a '1', '0'
+// or '-' for a don't care for each output, with the first output first, and
+// the returned terms are in order. Every other term has the returned
+// default values, which are '-' for an output whose off-set was given.
+func (r Result) expectedValues() ([]bitset, map[bitset]string, string) {
+	defaults := []byte(strings.Repeat("0", len(r.minterms)))
+	for o := range r.offSets {
+		if r.offSets[o] != nil {
+			defaults[o] = '-'
+		}
+	}
+
+	terms := []bitset{}
+	values := map[bitset]string{}
+	set := func(term bitset, o int, value byte) {
+		v, ok := values[term]
+		if !ok {
+			terms = append(terms, term)
+			v = string(defaults)
+		}
+		values[term] = v[:o] + string(value) + v[o+1:]
+	}
+
+	for o := range r.minterms {
+		for _, term := range r.minterms[o] {
+			set(term, o, '1')
+		}
+		for _, term := range r.dontCares[o] {
+			set(term, o, '-')
+		}
+		for _, term := range r.offSets[o] {
+			set(term, o, '0')
+		}
+	}
+
+	slices.SortFunc(terms, bitset.less)
+	return terms, values, string(defaults)
+}
+
+// stringifyProduct returns a string representation of an implicant as a
+// product of its literals, such as "a.b'". A product of no literals is "1".
+func stringifyProduct(im Implicant, inLabels InputLabels) string {
+	literals := []string{}
+	for j := 0; j < im.width; j++ {
+		bit := im.width - j - 1
+		if !im.im.xMask.bit(bit) {
+			literal := inLabels.Str(bit)
+			if !im.im.literals.bit(bit) {
+				literal += "'"
+			}
+			literals = append(literals, literal)
+		}
+	}
+
+	if len(literals) == 0 {
+		return "1"
+	}
+
+	return strings.Join(literals, ".")
+}
+
+// stringifySum returns a string representation of an implicant of the
+// off-set as the sum of its complemented literals, such as "(a' + b)". A sum
+// of no literals is "0".
+func stringifySum(im Implicant, inLabels InputLabels) string {
+	literals := []string{}
+	for j := 0; j < im.width; j++ {
+		bit := im.width - j - 1
+		if !im.im.xMask.bit(bit) {
+			literal := inLabels.Str(bit)
+			if im.im.literals.bit(bit) {
+				literal += "'"
+			}
+			literals = append(literals, literal)
+		}
+	}
+
+	if len(literals) == 0 {
+		return "0"
+	}
+	if len(literals) == 1 {
+		return literals[0]
+	}
+
+	return "(" + strings.Join(literals, " + ") + ")"
+}
+
+// String returns a representation of the cover with one equation per output,
+// such as "f = a.b' + c", with outputs and input bits printed using the labels
+// passed to GetMinimumCostCover. Constant outputs are rendered as "f = 0" or
+// "f = 1". If Err is set, its message is returned.
+func (r Result) String() string {
+	if r.Err != nil {
+		return r.Err.Error()
+	}
+
+	outputEquations := ""
+
+	for output, o := range r.Outputs {
+		terms := []string{}
+		separator := " + "
+
+		if o.Form == ProductOfSums {
+			for _, im := range o.Implicants {
+				terms = append(terms, stringifySum(im, r.inLabels))
+			}
+			separator = "."
+
+			// an empty product of sums is the constant 1
+			if len(terms) == 0 {
+				terms = append(terms, "1")
+			}
+		} else {
+			for _, im := range o.Implicants {
+				terms = append(terms, stringifyProduct(im, r.inLabels))
+			}
+
+			// an empty sum of products is the constant 0
+			if len(terms) == 0 {
+				terms = append(terms, "0")
+			}
+		}
+
+		outputEquations += r.outLabels.Str(output) + " = " + strings.Join(terms, separator) + "\n"
+	}
+
+	return outputEquations
+}
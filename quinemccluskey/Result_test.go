@@ -0,0 +1,70 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []testOutput
+		form    Form
+		want    string
+		// implicants lists the implicants of each output with their outputs
+		// and whether they are essential, followed by the cost and literals
+		// of the output
+		implicants []string
+		cost       float64
+	}{
+		{"shared term", []testOutput{{[]uint64{3, 7}, nil}, {[]uint64{3, 7, 4}, nil}}, SumOfProducts,
+			"y = b.c\nz = a.b'.c' + b.c\n", []string{"x11 [0 1] true", "1 2", "100 [1] true", "x11 [0 1] true", "2 5"}, 2},
+		{"cyclic", []testOutput{{[]uint64{0, 1, 2, 5, 6, 7}, nil}}, SumOfProducts,
+			"y = a'.b' + a.c + b.c'\n", []string{"00x [0] false", "1x1 [0] false", "x10 [0] false", "3 6"}, 3},
+		{"constants", []testOutput{{nil, nil}, {[]uint64{0, 1, 2, 3, 4, 5, 6, 7}, nil}}, SumOfProducts,
+			"y = 0\nz = 1\n", []string{"0 0", "xxx [1] true", "1 0"}, 1},
+		{"constants of sums", []testOutput{{nil, []uint64{1}}, {[]uint64{0, 1, 2, 3, 4, 5, 6, 7}, nil}}, ProductOfSums,
+			"y = 0\nz = 1\n", []string{"xxx [0] true", "1 0", "0 0"}, 1},
+		{"product of sums", []testOutput{{[]uint64{0, 1, 2, 3, 5}, []uint64{7}}}, ProductOfSums,
+			"y = (a' + c)\n", []string{"1x0 [0] true", "1 2"}, 1},
+	}
+
+	var inLabels InputLabels
+	inLabels.Init()
+	inLabels.Set(2, "a")
+	inLabels.Set(1, "b")
+	inLabels.Set(0, "c")
+	var outLabels OutputLabels
+	outLabels.Init()
+	outLabels.Add("y")
+	outLabels.Add("z")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newTestFunction(t, 3, test.outputs)
+			f.SetForm(test.form)
+			f.SetCoverSolver(PetrickSolver{})
+			r := f.GetMinimumCostCover(inLabels, outLabels)
+			checkResult(t, r, test.outputs)
+
+			implicants := []string{}
+			for _, output := range r.Outputs {
+				for i, im := range output.Implicants {
+					implicants = append(implicants, fmt.Sprintf("%s %v %v", im, im.Outputs(), output.Essential[i]))
+				}
+				implicants = append(implicants, fmt.Sprintf("%v %d", output.Cost, output.Literals))
+			}
+			if got := r.String(); got != test.want {
+				t.Errorf("String() = %q, want %q", got, test.want)
+			}
+			if !slices.Equal(implicants, test.implicants) {
+				t.Errorf("implicants are %q, want %q", implicants, test.implicants)
+			}
+			if r.Cost != test.cost || !r.Optimal {
+				t.Errorf("Cost = %v, Optimal = %v, want %v, true", r.Cost, r.Optimal, test.cost)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	// sigmaPiFunction matches a function such as "F(A,B,C) = ...", capturing
+	// its name, its inputs and the lists of terms which follow.
+	sigmaPiFunction = regexp.MustCompile(`^\s*([^\s()=]+)\s*\(([^()]*)\)\s*=\s*(.*?)\s*$`)
+	// sigmaPiList matches a list of terms such as "+ d(0,2)", capturing the
+	// operator, the sum or product symbol, the letter and the terms.
+	sigmaPiList = regexp.MustCompile(`^\s*([+.·*&]?)\s*(Σ|∑|Π|∏|(?i:sum|product|prod))?\s*([mMdD]?)\s*\(([^()]*)\)`)
+)
+
+// ParseSigmaPi parses one or more functions given in sigma or pi notation,
+// such as "F(A,B,C,D) = Σm(1,3,7,11) + d(0,2)" or "F(A,B,C) = ΠM(0,4) · D(5)",
+// each on its own line or separated by ';'. Every function must list the
+// same inputs, with the most significant first, which become the input
+// labels, and the names of the functions become the output labels. The
+// symbols "sum" and "prod" or "product" may be written instead of Σ and Π,
+// and "." or "*" instead of "·". The terms of a function in pi notation are
+// its maxterms, so its minterms are every other term which is not a don't
+// care. If the notation is malformed, or a function lists an input more than
+// once, ErrInvalidNotation is returned, if a term is listed as well as being a
+// don't care, ErrConflictingTerms is returned, and if a function in pi
+// notation has too many inputs for its terms to be enumerated,
+// ErrTooManyInputs is returned.
+func ParseSigmaPi(text string) (Specification, error) {
+	spec := Specification{}
+	onSets, dontCareSets := []map[string]bool{}, []map[string]bool{}
+
+	for _, function := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ';' }) {
+		if strings.TrimSpace(function) == "" {
+			continue
+		}
+
+		match := sigmaPiFunction.FindStringSubmatch(function)
+		if match == nil {
+			return Specification{}, fmt.Errorf("%w: %q is not a function such as F(A,B) = Σm(1,3)", ErrInvalidNotation, strings.TrimSpace(function))
+		}
+		name, rest := match[1], match[3]
+
+		inputs := strings.Split(match[2], ",")
+		for i := range inputs {
+			inputs[i] = strings.TrimSpace(inputs[i])
+			if inputs[i] == "" {
+				return Specification{}, fmt.Errorf("%w: %s: empty input name", ErrInvalidNotation, name)
+			}
+			if slices.Contains(inputs[:i], inputs[i]) {
+				return Specification{}, fmt.Errorf("%w: %s: input %s is listed more than once", ErrInvalidNotation, name, inputs[i])
+			}
+		}
+		if spec.InputLabels == nil {
+			spec.InputLabels, spec.NumInputs = inputs, len(inputs)
+		} else if !slices.Equal(spec.InputLabels, inputs) {
+			return Specification{}, fmt.Errorf("%w: %s: inputs differ from those of %s", ErrInvalidNotation, name, spec.OutputLabels[0])
+		}
+
+		// the first list holds the minterms or maxterms, and may be followed
+		// by a list of don't cares
+		var pi bool
+		var terms, dontCares map[string]bool
+		for rest != "" {
+			list := sigmaPiList.FindStringSubmatch(rest)
+			if list == nil {
+				return Specification{}, fmt.Errorf("%w: %s: unexpected %q", ErrInvalidNotation, name, rest)
+			}
+			rest = strings.TrimSpace(rest[len(list[0]):])
+
+			operator, symbol, letter := list[1], strings.ToLower(list[2]), strings.ToLower(list[3])
+			isPi := symbol == "π" || symbol == "∏" || strings.HasPrefix(symbol, "prod")
+
+			set, err := sigmaPiTerms(list[4], spec.NumInputs)
+			if err != nil {
+				return Specification{}, fmt.Errorf("%w: %s: %v", ErrInvalidNotation, name, err)
+			}
+
+			switch {
+			case terms == nil && operator == "" && symbol != "" && letter != "d":
+				pi, terms = isPi, set
+			case terms != nil && dontCares == nil && letter == "d" && (symbol == "" || isPi == pi):
+				dontCares = set
+			default:
+				return Specification{}, fmt.Errorf("%w: %s: unexpected %q", ErrInvalidNotation, name, strings.TrimSpace(list[0]))
+			}
+		}
+		if terms == nil {
+			return Specification{}, fmt.Errorf("%w: %s: missing list of terms", ErrInvalidNotation, name)
+		}
+		if dontCares == nil {
+			dontCares = map[string]bool{}
+		}
+
+		for term := range dontCares {
+			if terms[term] {
+				return Specification{}, fmt.Errorf("%w: %s: %s is both listed and a don't care", ErrConflictingTerms, name, decimalTerm(term))
+			}
+		}
+
+		// the minterms of a function in pi notation are the terms which are
+		// neither maxterms nor don't cares
+		if pi {
+			if spec.NumInputs > maxtermInputLimit {
+				return Specification{}, fmt.Errorf("%w: %s: %d inputs exceeds the limit of %d for pi notation", ErrTooManyInputs, name, spec.NumInputs, maxtermInputLimit)
+			}
+
+			minterms := map[string]bool{}
+			for t := uint64(0); t < 1<<spec.NumInputs; t++ {
+				term := newBitset(t).binary(spec.NumInputs)
+				if !terms[term] && !dontCares[term] {
+					minterms[term] = true
+				}
+			}
+			terms = minterms
+		}
+
+		spec.OutputLabels = append(spec.OutputLabels, name)
+		onSets = append(onSets, terms)
+		dontCareSets = append(dontCareSets, dontCares)
+	}
+
+	if len(onSets) == 0 {
+		return Specification{}, fmt.Errorf("%w: no functions", ErrInvalidNotation)
+	}
+
+	spec.NumOutputs = len(onSets)
+	spec.setTerms(onSets, dontCareSets, nil)
+	return spec, nil
+}
+
+// sigmaPiTerms returns the set of terms of numInputs inputs given by a comma
+// separated list of decimal numbers, as strings of '1's and '0's.
+func sigmaPiTerms(list string, numInputs int) (map[string]bool, error) {
+	terms := map[string]bool{}
+	if strings.TrimSpace(list) == "" {
+		return terms, nil
+	}
+
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		v, err := strconv.ParseUint(item, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid term %q", item)
+		}
+
+		term := newBitset(v)
+		if term.msbPos() > numInputs {
+			return nil, fmt.Errorf("%d is not a term of %d inputs", v, numInputs)
+		}
+		terms[term.binary(numInputs)] = true
+	}
+
+	return terms, nil
+}
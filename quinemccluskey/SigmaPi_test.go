@@ -0,0 +1,86 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSigmaPi(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Specification
+	}{
+		{"sigma", "F(A,B) = Σm(1,3)", Specification{
+			NumInputs: 2, NumOutputs: 1, InputLabels: []string{"A", "B"}, OutputLabels: []string{"F"},
+			Minterms: [][]string{{"01", "11"}}, DontCares: [][]string{{}},
+		}},
+		{"pi", "F(A,B,C) = ΠM(0,4) · D(5)", Specification{
+			NumInputs: 3, NumOutputs: 1, InputLabels: []string{"A", "B", "C"}, OutputLabels: []string{"F"},
+			Minterms: [][]string{{"001", "010", "011", "110", "111"}}, DontCares: [][]string{{"101"}},
+		}},
+		{"words", "F(A,B) = sum m(0) + d(3); G(A,B) = prod(1)", Specification{
+			NumInputs: 2, NumOutputs: 2, InputLabels: []string{"A", "B"}, OutputLabels: []string{"F", "G"},
+			Minterms: [][]string{{"00"}, {"00", "10", "11"}}, DontCares: [][]string{{"11"}, {}},
+		}},
+		{"lines and spaces", "\n  F ( A , B ) = ∑ ( 2 ) \n\nG(A,B)=∏M(0)*D(1)\n", Specification{
+			NumInputs: 2, NumOutputs: 2, InputLabels: []string{"A", "B"}, OutputLabels: []string{"F", "G"},
+			Minterms: [][]string{{"10"}, {"10", "11"}}, DontCares: [][]string{{}, {"01"}},
+		}},
+		{"no terms", "F(A) = Σm()", Specification{
+			NumInputs: 1, NumOutputs: 1, InputLabels: []string{"A"}, OutputLabels: []string{"F"},
+			Minterms: [][]string{{}}, DontCares: [][]string{{}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec, err := ParseSigmaPi(test.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(spec, test.want) {
+				t.Errorf("ParseSigmaPi(%q) = %+v, want %+v", test.text, spec, test.want)
+			}
+		})
+	}
+}
+
+func TestParseSigmaPiErrors(t *testing.T) {
+	inputs := make([]string, maxtermInputLimit+1)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("x%d", i)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want error
+	}{
+		{"no functions", " \n; ", ErrInvalidNotation},
+		{"no inputs", "F = Σm(1)", ErrInvalidNotation},
+		{"empty input name", "F(A,) = Σm(1)", ErrInvalidNotation},
+		{"repeated input", "F(A,A) = Σm(1)", ErrInvalidNotation},
+		{"different inputs", "F(A,B) = Σm(1); G(A,C) = Σm(1)", ErrInvalidNotation},
+		{"term too large", "F(A) = Σm(2)", ErrInvalidNotation},
+		{"invalid term", "F(A) = Σm(x)", ErrInvalidNotation},
+		{"only don't cares", "F(A) = d(0)", ErrInvalidNotation},
+		{"missing terms", "F(A) =", ErrInvalidNotation},
+		{"two lists", "F(A) = Σm(0) + Σm(1)", ErrInvalidNotation},
+		{"mixed notation", "F(A,B) = Σm(1) + ΠD(0)", ErrInvalidNotation},
+		{"trailing text", "F(A) = Σm(0) and more", ErrInvalidNotation},
+		{"listed and don't care", "F(A) = Σm(0) + d(0)", ErrConflictingTerms},
+		{"pi too large", fmt.Sprintf("F(%s) = ΠM(0)", strings.Join(inputs, ",")), ErrTooManyInputs},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseSigmaPi(test.text); !errors.Is(err, test.want) {
+				t.Errorf("ParseSigmaPi(%q) error = %v, want %v", test.text, err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Specification is a LogicFunction given by the minterms and don't cares of
+// each output, as read by ReadPLA, ReadTruthTable, ReadROM or ParseSigmaPi.
+type Specification struct {
+	// NumInputs and NumOutputs are the number of inputs and outputs.
+	NumInputs  int
+	NumOutputs int
+	// InputLabels and OutputLabels name the inputs, with the most significant
+	// first, and the outputs. They are empty if no names were given.
+	InputLabels  []string
+	OutputLabels []string
+	// Minterms and DontCares hold a sorted set of terms for each output, as
+	// strings of NumInputs '1's and '0's with the most significant input
+	// first.
+	Minterms  [][]string
+	DontCares [][]string
+	// OffSets is nil unless the off-set of some output is given, as by a PLA
+	// of type fr, in which case it holds the sorted off-set of each output,
+	// or nil for an output whose off-set is not given. Every term of an
+	// output with an off-set which is in neither its minterms nor its
+	// off-set is a don't care, and its DontCares is empty.
+	OffSets [][]string
+}
+
+// setTerms fills the minterms, don't cares and off-sets of the calling
+// Specification from the on-set, don't care set and off-set of each output.
+// The off-sets may be nil, as may the off-set of an output which is not
+// given. Terms in the don't care set of an output are treated as don't cares.
+func (spec *Specification) setTerms(onSets []map[string]bool, dontCareSets []map[string]bool, offSets []map[string]bool) {
+	spec.Minterms, spec.DontCares, spec.OffSets = [][]string{}, [][]string{}, nil
+
+	// sorted returns the terms of a set which are not don't cares, in order
+	sorted := func(set map[string]bool, dontCares map[string]bool) []string {
+		terms := []string{}
+		for term := range set {
+			if !dontCares[term] {
+				terms = append(terms, term)
+			}
+		}
+
+		// terms of equal length are ordered by value
+		sort.Strings(terms)
+		return terms
+	}
+
+	for o := range onSets {
+		spec.Minterms = append(spec.Minterms, sorted(onSets[o], dontCareSets[o]))
+
+		if offSets == nil || offSets[o] == nil {
+			spec.DontCares = append(spec.DontCares, sorted(dontCareSets[o], nil))
+			continue
+		}
+
+		if spec.OffSets == nil {
+			spec.OffSets = make([][]string, len(onSets))
+		}
+		spec.OffSets[o] = sorted(offSets[o], dontCareSets[o])
+		spec.DontCares = append(spec.DontCares, []string{})
+	}
+}
+
+// expandCube returns the minterms contained in a cube of '1's, '0's and '-'s.
+// If the cube has more than maxtermInputLimit '-'s, ErrTooManyInputs is
+// returned.
+func expandCube(cube string) ([]string, error) {
+	dashes := []int{}
+	for i := 0; i < len(cube); i++ {
+		if cube[i] == '-' {
+			dashes = append(dashes, i)
+		}
+	}
+
+	if len(dashes) > maxtermInputLimit {
+		return nil, fmt.Errorf("%w: cube %s has more than %d '-' inputs", ErrTooManyInputs, cube, maxtermInputLimit)
+	}
+
+	terms := []string{}
+	term := []byte(cube)
+	for combination := 0; combination < 1<<len(dashes); combination++ {
+		for i, position := range dashes {
+			term[position] = '0' + byte(combination>>(len(dashes)-1-i)&1)
+		}
+		terms = append(terms, string(term))
+	}
+
+	return terms, nil
+}
+
+// Labels returns the input and output labels of the Specification, for use
+// with GetMinimumCostCover.
+func (spec Specification) Labels() (InputLabels, OutputLabels) {
+	var inLabels InputLabels
+	for i, label := range spec.InputLabels {
+		inLabels.Set(spec.NumInputs-1-i, label)
+	}
+
+	var outLabels OutputLabels
+	for _, label := range spec.OutputLabels {
+		outLabels.Add(label)
+	}
+
+	return inLabels, outLabels
+}
+
+// AddSpecification declares the number of inputs of the LogicFunction using
+// SetNumInputs, and adds each output of the Specification using
+// AddOutputTerms, or AddOutputOffSetTerms if its off-set is given.
+func (solver *LogicFunction) AddSpecification(spec Specification) error {
+	if err := solver.SetNumInputs(spec.NumInputs); err != nil {
+		return err
+	}
+
+	for o := range spec.Minterms {
+		var err error
+		if spec.OffSets != nil && spec.OffSets[o] != nil {
+			err = solver.AddOutputOffSetTerms(spec.Minterms[o], spec.OffSets[o])
+		} else {
+			err = solver.AddOutputTerms(spec.Minterms[o], spec.DontCares[o])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package quinemccluskey
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// truthTableCell is the value given to a term of an output by a row of a
+// truth table, with the line of the row.
+type truthTableCell struct {
+	value byte
+	line  int
+}
+
+// ReadTruthTable reads a truth table from r as comma separated values, or
+// values separated by the passed separator, such as '\t' for tab separated
+// values. The first row is a header naming each column, and the first
+// numInputs columns are the inputs, with the most significant first, followed
+// by the outputs. If numInputs is 0, the inputs are instead separated from the
+// outputs by a column with an empty name, which is ignored. The header names
+// become the input and output labels. Each other row gives a cube of the
+// inputs, with '0', '1', or '-' or 'x' for an input which may take either
+// value, and the value of each output for every term in the cube, which is
+// '0', '1', or 'x' or '-' for a don't care. Terms given by no row are 0, and
+// lines starting with '#' are ignored. If the truth table is malformed,
+// ErrInvalidTruthTable is returned, if two rows give different values for a
+// term of an output, ErrConflictingTerms is returned, and if a cube has too
+// many '-'s to be expanded, ErrTooManyInputs is returned.
+func ReadTruthTable(r io.Reader, comma rune, numInputs int) (Specification, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.Comment = '#'
+	// leading white space would otherwise swallow the empty fields of tab
+	// separated values, and values are trimmed in any case
+	reader.TrimLeadingSpace = !unicode.IsSpace(comma)
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return Specification{}, fmt.Errorf("%w: missing header", ErrInvalidTruthTable)
+	}
+	if err != nil {
+		return Specification{}, fmt.Errorf("%w: %v", ErrInvalidTruthTable, err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	// the inputs end at the separator column, if there is one
+	separator := numInputs
+	if numInputs < 0 || numInputs >= len(header) {
+		return Specification{}, fmt.Errorf("%w: %d columns do not hold %d inputs and any outputs", ErrInvalidTruthTable, len(header), numInputs)
+	}
+	if numInputs == 0 {
+		separator = -1
+		for i, name := range header {
+			if name == "" {
+				separator = i
+				break
+			}
+		}
+		if separator < 0 {
+			return Specification{}, fmt.Errorf("%w: no empty column separates the inputs from the outputs", ErrInvalidTruthTable)
+		}
+	}
+
+	spec := Specification{InputLabels: header[:separator]}
+	spec.OutputLabels = header[separator:]
+	if numInputs == 0 {
+		spec.OutputLabels = header[separator+1:]
+	}
+	spec.NumInputs, spec.NumOutputs = len(spec.InputLabels), len(spec.OutputLabels)
+	if spec.NumInputs == 0 || spec.NumOutputs == 0 {
+		return Specification{}, fmt.Errorf("%w: %d columns do not hold both inputs and outputs", ErrInvalidTruthTable, len(header))
+	}
+
+	cells := make([]map[string]truthTableCell, spec.NumOutputs)
+	for o := range cells {
+		cells[o] = map[string]truthTableCell{}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseError *csv.ParseError
+			if errors.As(err, &parseError) && errors.Is(parseError.Err, csv.ErrFieldCount) {
+				return Specification{}, fmt.Errorf("%w: line %d: expected %d columns", ErrInvalidTruthTable, parseError.Line, len(header))
+			}
+			return Specification{}, fmt.Errorf("%w: %v", ErrInvalidTruthTable, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		cube := []byte{}
+		for i := 0; i < spec.NumInputs; i++ {
+			switch value := strings.TrimSpace(record[i]); value {
+			case "0", "1":
+				cube = append(cube, value[0])
+			case "-", "x", "X":
+				cube = append(cube, '-')
+			default:
+				return Specification{}, fmt.Errorf("%w: line %d: input %s is %q", ErrInvalidTruthTable, line, header[i], value)
+			}
+		}
+
+		terms, err := expandCube(string(cube))
+		if err != nil {
+			return Specification{}, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		for o := 0; o < spec.NumOutputs; o++ {
+			column := len(header) - spec.NumOutputs + o
+			value := strings.TrimSpace(record[column])
+			switch value {
+			case "0", "1":
+			case "x", "X", "-":
+				value = "x"
+			default:
+				return Specification{}, fmt.Errorf("%w: line %d: output %s is %q", ErrInvalidTruthTable, line, header[column], value)
+			}
+
+			for _, term := range terms {
+				cell, ok := cells[o][term]
+				if !ok {
+					cells[o][term] = truthTableCell{value[0], line}
+				} else if cell.value != value[0] {
+					return Specification{}, fmt.Errorf("%w: line %d: output %s of term %s conflicts with line %d", ErrConflictingTerms, line, header[column], term, cell.line)
+				}
+			}
+		}
+	}
+
+	onSets, dontCareSets := []map[string]bool{}, []map[string]bool{}
+	for o := range cells {
+		onSets = append(onSets, map[string]bool{})
+		dontCareSets = append(dontCareSets, map[string]bool{})
+		for term, cell := range cells[o] {
+			switch cell.value {
+			case '1':
+				onSets[o][term] = true
+			case 'x':
+				dontCareSets[o][term] = true
+			}
+		}
+	}
+
+	spec.setTerms(onSets, dontCareSets, nil)
+	return spec, nil
+}
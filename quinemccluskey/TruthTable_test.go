@@ -0,0 +1,97 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadTruthTable(t *testing.T) {
+	tests := []struct {
+		name      string
+		table     string
+		comma     rune
+		numInputs int
+		want      Specification
+	}{
+		{"separator column", "a,b,,y,z\n0,0,,1,0\n1,-,,x,1\n", ',', 0, Specification{
+			NumInputs: 2, NumOutputs: 2, InputLabels: []string{"a", "b"}, OutputLabels: []string{"y", "z"},
+			Minterms: [][]string{{"00"}, {"10", "11"}}, DontCares: [][]string{{"10", "11"}, {}},
+		}},
+		{"counted inputs", "a,b,y\n1,x,1\n0,1,-\n", ',', 2, Specification{
+			NumInputs: 2, NumOutputs: 1, InputLabels: []string{"a", "b"}, OutputLabels: []string{"y"},
+			Minterms: [][]string{{"10", "11"}}, DontCares: [][]string{{"01"}},
+		}},
+		{"tabs and comments", "# comment\na\t\ty\n# 1\t\t1\n 1\t\t X\n", '\t', 0, Specification{
+			NumInputs: 1, NumOutputs: 1, InputLabels: []string{"a"}, OutputLabels: []string{"y"},
+			Minterms: [][]string{{}}, DontCares: [][]string{{"1"}},
+		}},
+		{"agreeing rows", "a,b,,y\n-,1,,1\n1,1,,1\n0,0,,0\n", ',', 0, Specification{
+			NumInputs: 2, NumOutputs: 1, InputLabels: []string{"a", "b"}, OutputLabels: []string{"y"},
+			Minterms: [][]string{{"01", "11"}}, DontCares: [][]string{{}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec, err := ReadTruthTable(strings.NewReader(test.table), test.comma, test.numInputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(spec, test.want) {
+				t.Errorf("ReadTruthTable() = %+v, want %+v", spec, test.want)
+			}
+		})
+	}
+}
+
+func TestReadTruthTableErrors(t *testing.T) {
+	wide := strings.Repeat("a,", maxtermInputLimit+1) + ",y\n" + strings.Repeat("-,", maxtermInputLimit+1) + ",1\n"
+
+	tests := []struct {
+		name      string
+		table     string
+		numInputs int
+		want      error
+	}{
+		{"missing header", "", 0, ErrInvalidTruthTable},
+		{"no separator", "a,b,y\n", 0, ErrInvalidTruthTable},
+		{"negative inputs", "a,b,y\n", -1, ErrInvalidTruthTable},
+		{"no outputs after inputs", "a,b,y\n", 3, ErrInvalidTruthTable},
+		{"no inputs", ",y\n", 0, ErrInvalidTruthTable},
+		{"no outputs", "a,\n", 0, ErrInvalidTruthTable},
+		{"columns", "a,,y\n0,,1,1\n", 0, ErrInvalidTruthTable},
+		{"quotes", "a,,y\n\"0,,1\n", 0, ErrInvalidTruthTable},
+		{"invalid input", "a,,y\n2,,1\n", 0, ErrInvalidTruthTable},
+		{"invalid output", "a,,y\n0,,2\n", 0, ErrInvalidTruthTable},
+		{"conflicting rows", "a,,y\n0,,1\n-,,0\n", 0, ErrConflictingTerms},
+		{"don't care and 1", "a,,y\n0,,1\n0,,x\n", 0, ErrConflictingTerms},
+		{"cube too large", wide, 0, ErrTooManyInputs},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ReadTruthTable(strings.NewReader(test.table), ',', test.numInputs); !errors.Is(err, test.want) {
+				t.Errorf("ReadTruthTable(%q, %d) error = %v, want %v", test.table, test.numInputs, err, test.want)
+			}
+		})
+	}
+}
+
+func TestReadTruthTableSolve(t *testing.T) {
+	spec, err := ReadTruthTable(strings.NewReader("a,b,,y\n0,0,,0\n0,1,,1\n1,0,,1\n1,1,,x\n"), ',', 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f LogicFunction
+	f.Init(false)
+	if err := f.AddSpecification(spec); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(f.GetMinimumCostCover(spec.Labels())); !strings.Contains(got, "y = a + b") && !strings.Contains(got, "y = b + a") {
+		t.Errorf("cover is\n%s\nwant y = a + b", got)
+	}
+}
@@ -0,0 +1,340 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VHDLOptions controls the entity written by WriteVHDL, and the testbench
+// written by WriteVHDLTestbench.
+type VHDLOptions struct {
+	// EntityName is the name of the entity, and the testbench is named by
+	// appending "_tb". If empty, the entity is named "logic_function".
+	EntityName string
+	// Buses groups the inputs, and the outputs, labelled as bits of a bus,
+	// such as "addr[3]" to "addr[0]", into a single std_logic_vector port. A
+	// label which is not a bit of a bus, or whose bus does not have a
+	// contiguous range of bits, is a port of its own.
+	Buses bool
+}
+
+// vhdlKeywords holds the reserved words of VHDL, which cannot be used as
+// identifiers.
+var vhdlKeywords = map[string]bool{}
+
+func init() {
+	for _, keyword := range strings.Fields(`abs access after alias all and
+		architecture array assert assume assume_guarantee attribute begin block
+		body buffer bus case component configuration constant context cover
+		default disconnect downto else elsif end entity exit fairness file for
+		force function generate generic group guarded if impure in inertial
+		inout is label library linkage literal loop map mod nand new next nor
+		not null of on open or others out package parameter port postponed
+		procedure process property protected pure range record register reject
+		release rem report restrict restrict_guarantee return rol ror select
+		sequence severity shared signal sla sll sra srl strong subtype then to
+		transport type unaffected units until use variable vmode vprop vunit
+		wait when while with xnor xor`) {
+		vhdlKeywords[keyword] = true
+	}
+}
+
+// vhdlIdentifier returns a legal VHDL identifier for a label, in which each
+// run of characters that may not appear in an identifier, or of '_'s, is
+// replaced by a single '_', or dropped at either end of the label, so
+// "addr[3]" becomes "addr_3". Labels which do not start with a letter, and
+// reserved words, are prefixed by "s_".
+func vhdlIdentifier(label string) string {
+	identifier := []byte{}
+	replaced := false
+	for _, r := range label {
+		if r >= 0x80 || !('0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			replaced = true
+			continue
+		}
+		if replaced && len(identifier) > 0 {
+			identifier = append(identifier, '_')
+		}
+		identifier, replaced = append(identifier, byte(r)), false
+	}
+
+	if len(identifier) == 0 {
+		return "s"
+	}
+	if identifier[0] <= '9' || vhdlKeywords[strings.ToLower(string(identifier))] {
+		return "s_" + string(identifier)
+	}
+
+	return string(identifier)
+}
+
+// vhdlTerm returns a VHDL expression for an implicant, as the product of its
+// literals, such as "a and not b", or for the ProductOfSums form, the sum of
+// its complemented literals, such as "not a or b". The passed signals give the
+// expression for each input bit.
+func vhdlTerm(im Implicant, form Form, signals []string) string {
+	literals := []string{}
+	for bit := im.width - 1; bit >= 0; bit-- {
+		if im.im.xMask.bit(bit) {
+			continue
+		}
+		if im.im.literals.bit(bit) == (form == ProductOfSums) {
+			literals = append(literals, "not "+signals[bit])
+		} else {
+			literals = append(literals, signals[bit])
+		}
+	}
+
+	if form == ProductOfSums {
+		if len(literals) == 0 {
+			return "'0'"
+		}
+		return strings.Join(literals, " or ")
+	}
+
+	if len(literals) == 0 {
+		return "'1'"
+	}
+	return strings.Join(literals, " and ")
+}
+
+// vhdlEntity holds the names chosen for the entity of a Result and its ports.
+type vhdlEntity struct {
+	name string
+	// ports holds the declaration of each port.
+	ports []string
+	// inputs and outputs hold the name of the signal of each input bit and
+	// each output.
+	inputs  []string
+	outputs []string
+	// used holds the identifiers declared by the entity, in lower case.
+	used map[string]bool
+}
+
+// newVHDLEntity chooses the names of the entity of a Result and its ports.
+func newVHDLEntity(r Result, options VHDLOptions) vhdlEntity {
+	entityName := options.EntityName
+	if entityName == "" {
+		entityName = "logic_function"
+	}
+
+	entity := vhdlEntity{
+		used:    map[string]bool{},
+		inputs:  make([]string, r.NumInputs),
+		outputs: make([]string, len(r.Outputs)),
+	}
+	entity.name = uniqueIdentifier(vhdlIdentifier(entityName), entity.used, strings.ToLower)
+
+	inLabels, outLabels := portLabels(r)
+	inputs := make([]string, r.NumInputs)
+	for _, direction := range []struct {
+		mode    string
+		labels  []string
+		signals []string
+	}{{"in ", inLabels, inputs}, {"out", outLabels, entity.outputs}} {
+		for _, p := range groupPorts(direction.labels, options.Buses) {
+			name := uniqueIdentifier(vhdlIdentifier(p.label), entity.used, strings.ToLower)
+			if p.bus {
+				entity.ports = append(entity.ports, fmt.Sprintf("%s : %s std_logic_vector(%d downto %d)", name, direction.mode, p.msb, p.lsb))
+			} else {
+				entity.ports = append(entity.ports, fmt.Sprintf("%s : %s std_logic", name, direction.mode))
+			}
+
+			for signal, bit := range p.bits {
+				direction.signals[signal] = name
+				if p.bus {
+					direction.signals[signal] = fmt.Sprintf("%s(%d)", name, bit)
+				}
+			}
+		}
+	}
+
+	// input bit i is the signal at position NumInputs-1-i
+	for i := range entity.inputs {
+		entity.inputs[i] = inputs[r.NumInputs-1-i]
+	}
+
+	return entity
+}
+
+// WriteVHDL writes the cover held by a Result to w as a VHDL entity, with a
+// std_logic port for each input and output named from the labels passed to
+// GetMinimumCostCover, and an architecture giving each output by a concurrent
+// signal assignment of its equation. Labels are sanitized to legal
+// identifiers, and made unique. Each term shared by the equations of several
+// outputs is assigned to a signal of its own. If the Result's Err is set, it
+// is returned.
+func WriteVHDL(w io.Writer, r Result, options VHDLOptions) error {
+	if r.Err != nil {
+		return r.Err
+	}
+
+	entity := newVHDLEntity(r, options)
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "library ieee;\nuse ieee.std_logic_1164.all;\n\n")
+	fmt.Fprintf(&b, "entity %s is\n    port (\n", entity.name)
+	for i, port := range entity.ports {
+		separator := ";"
+		if i == len(entity.ports)-1 {
+			separator = ""
+		}
+		fmt.Fprintf(&b, "        %s%s\n", port, separator)
+	}
+	fmt.Fprintf(&b, "    );\nend entity %s;\n\n", entity.name)
+
+	terms, implicants := sharedTerms(r)
+	signals := sharedNames(terms, entity.used, strings.ToLower)
+
+	fmt.Fprintf(&b, "architecture rtl of %s is\n", entity.name)
+	for _, term := range terms {
+		fmt.Fprintf(&b, "    signal %s : std_logic;\n", signals[term])
+	}
+	fmt.Fprintf(&b, "begin\n")
+	for _, term := range terms {
+		fmt.Fprintf(&b, "    %s <= %s;\n", signals[term], vhdlTerm(implicants[term], term.form, entity.inputs))
+	}
+	if len(terms) > 0 {
+		fmt.Fprintf(&b, "\n")
+	}
+
+	for o, output := range r.Outputs {
+		terms := []string{}
+		for _, im := range output.Implicants {
+			term := vhdlTerm(im, output.Form, entity.inputs)
+			if signal, ok := signals[sharedTerm{output.Form, im.String()}]; ok {
+				term = signal
+			} else if len(output.Implicants) > 1 && im.LiteralCount() > 1 {
+				term = "(" + term + ")"
+			}
+			terms = append(terms, term)
+		}
+
+		equation := ""
+		switch {
+		case output.Form == ProductOfSums && len(terms) == 0:
+			equation = "'1'"
+		case output.Form == ProductOfSums:
+			equation = strings.Join(terms, " and ")
+		case len(terms) == 0:
+			equation = "'0'"
+		default:
+			equation = strings.Join(terms, " or ")
+		}
+
+		fmt.Fprintf(&b, "    %s <= %s;\n", entity.outputs[o], equation)
+	}
+
+	fmt.Fprintf(&b, "end architecture rtl;\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteVHDLTestbench writes to w a self-checking VHDL testbench for the
+// entity written by WriteVHDL with the same options. The testbench applies
+// every term of the inputs in turn, and reports an error for each term where
+// an output differs from the function whose minterms and don't cares were
+// added to the LogicFunction. A constant function of no inputs is checked
+// for its single term, with no inputs to apply. If the function has more than
+// maxtermInputLimit inputs, ErrTooManyInputs is returned, and if the Result's
+// Err is set, it is returned.
+func WriteVHDLTestbench(w io.Writer, r Result, options VHDLOptions) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if r.NumInputs > maxtermInputLimit {
+		return fmt.Errorf("%w: %d inputs exceeds the limit of %d for a testbench", ErrTooManyInputs, r.NumInputs, maxtermInputLimit)
+	}
+
+	entity := newVHDLEntity(r, options)
+	testbench := entity.name + "_tb"
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "library ieee;\nuse ieee.std_logic_1164.all;\nuse ieee.numeric_std.all;\n\n")
+	fmt.Fprintf(&b, "entity %s is\nend entity %s;\n\n", testbench, testbench)
+
+	// output o is bit len(r.Outputs)-1-o of the outputs, so the expected
+	// values of each term are written with the first output first
+	fmt.Fprintf(&b, "architecture test of %s is\n", testbench)
+	if r.NumInputs > 0 {
+		fmt.Fprintf(&b, "    signal inputs : std_logic_vector(%d downto 0);\n", r.NumInputs-1)
+	}
+	fmt.Fprintf(&b, "    signal outputs : std_logic_vector(%d downto 0);\n\n", len(r.Outputs)-1)
+	fmt.Fprintf(&b, "    -- expected returns the outputs of the specified function for a term,\n")
+	fmt.Fprintf(&b, "    -- where '-' is a don't care\n")
+	fmt.Fprintf(&b, "    function expected(term : natural) return std_logic_vector is\n")
+	fmt.Fprintf(&b, "    begin\n        case term is\n")
+
+	// terms with the same values share a choice
+	terms, values, defaults := r.expectedValues()
+	choices := map[string][]string{}
+	order := []string{}
+	for _, term := range terms {
+		value := values[term]
+		if value == defaults {
+			continue
+		}
+		if _, ok := choices[value]; !ok {
+			order = append(order, value)
+		}
+		choices[value] = append(choices[value], term.decimal())
+	}
+	for _, value := range order {
+		line := "            when "
+		for i, choice := range choices[value] {
+			if i > 0 {
+				line += " |"
+				if len(line)+len(choice) > 76 {
+					fmt.Fprintf(&b, "%s\n", line)
+					line = "                "
+				} else {
+					line += " "
+				}
+			}
+			line += choice
+		}
+		fmt.Fprintf(&b, "%s => return \"%s\";\n", line, value)
+	}
+	fmt.Fprintf(&b, "            when others => return \"%s\";\n", defaults)
+	fmt.Fprintf(&b, "        end case;\n    end function expected;\n")
+
+	fmt.Fprintf(&b, "begin\n")
+	fmt.Fprintf(&b, "    dut : entity work.%s\n        port map (\n", entity.name)
+	associations := []string{}
+	for i := r.NumInputs - 1; i >= 0; i-- {
+		associations = append(associations, fmt.Sprintf("%s => inputs(%d)", entity.inputs[i], i))
+	}
+	for o := range r.Outputs {
+		associations = append(associations, fmt.Sprintf("%s => outputs(%d)", entity.outputs[o], len(r.Outputs)-1-o))
+	}
+	fmt.Fprintf(&b, "            %s\n        );\n\n", strings.Join(associations, ",\n            "))
+
+	fmt.Fprintf(&b, "    stimulus : process\n")
+	fmt.Fprintf(&b, "        variable errors : natural := 0;\n")
+	fmt.Fprintf(&b, "    begin\n")
+	fmt.Fprintf(&b, "        for term in 0 to 2 ** %d - 1 loop\n", r.NumInputs)
+	if r.NumInputs > 0 {
+		fmt.Fprintf(&b, "            inputs <= std_logic_vector(to_unsigned(term, %d));\n", r.NumInputs)
+	}
+	fmt.Fprintf(&b, "            wait for 1 ns;\n")
+	fmt.Fprintf(&b, "            if not std_match(outputs, expected(term)) then\n")
+	fmt.Fprintf(&b, "                report \"term \" & integer'image(term) & \" gives the wrong outputs\"\n")
+	fmt.Fprintf(&b, "                    severity error;\n")
+	fmt.Fprintf(&b, "                errors := errors + 1;\n")
+	fmt.Fprintf(&b, "            end if;\n")
+	fmt.Fprintf(&b, "        end loop;\n\n")
+	fmt.Fprintf(&b, "        assert errors = 0\n")
+	fmt.Fprintf(&b, "            report integer'image(errors) & \" terms give the wrong outputs\"\n")
+	fmt.Fprintf(&b, "            severity failure;\n")
+	fmt.Fprintf(&b, "        report \"every term gives the expected outputs\";\n")
+	fmt.Fprintf(&b, "        wait;\n")
+	fmt.Fprintf(&b, "    end process stimulus;\n")
+	fmt.Fprintf(&b, "end architecture test;\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
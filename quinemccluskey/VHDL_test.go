@@ -0,0 +1,70 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteVHDL(t *testing.T) {
+	tests := []struct {
+		name    string
+		form    Form
+		options VHDLOptions
+	}{
+		{"vhdl_sop", SumOfProducts, VHDLOptions{}},
+		{"vhdl_buses", SumOfProducts, VHDLOptions{EntityName: "decoder", Buses: true}},
+		{"vhdl_pos", ProductOfSums, VHDLOptions{EntityName: "entity"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := goldenResult(t, test.form)
+
+			var b strings.Builder
+			if err := WriteVHDL(&b, r, test.options); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, test.name, b.String())
+
+			b.Reset()
+			if err := WriteVHDLTestbench(&b, r, test.options); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, test.name+"_tb", b.String())
+		})
+	}
+
+	// a constant function has no inputs to declare or apply
+	var f LogicFunction
+	f.Init(false)
+	if err := f.AddOutputTerms([]string{""}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddOutputTerms(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+	var b strings.Builder
+	if err := WriteVHDL(&b, r, VHDLOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "vhdl_constant", b.String())
+	b.Reset()
+	if err := WriteVHDLTestbench(&b, r, VHDLOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "vhdl_constant_tb", b.String())
+
+	r = Result{Err: ErrNoOutputs}
+	if err := WriteVHDL(&strings.Builder{}, r, VHDLOptions{}); !errors.Is(err, ErrNoOutputs) {
+		t.Errorf("WriteVHDL() of a Result with an error = %v, want %v", err, ErrNoOutputs)
+	}
+	if err := WriteVHDLTestbench(&strings.Builder{}, r, VHDLOptions{}); !errors.Is(err, ErrNoOutputs) {
+		t.Errorf("WriteVHDLTestbench() of a Result with an error = %v, want %v", err, ErrNoOutputs)
+	}
+	r = Result{NumInputs: maxtermInputLimit + 1}
+	if err := WriteVHDLTestbench(&strings.Builder{}, r, VHDLOptions{}); !errors.Is(err, ErrTooManyInputs) {
+		t.Errorf("WriteVHDLTestbench() of %d inputs = %v, want %v", maxtermInputLimit+1, err, ErrTooManyInputs)
+	}
+}
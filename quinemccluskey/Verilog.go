@@ -0,0 +1,211 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VerilogOptions controls the module written by WriteVerilog.
+type VerilogOptions struct {
+	// ModuleName is the name of the module. If empty, the module is named
+	// "logic_function".
+	ModuleName string
+	// Buses groups the inputs, and the outputs, labelled as bits of a bus,
+	// such as "addr[3]" to "addr[0]", into a single vector port. A label
+	// which is not a bit of a bus, or whose bus does not have a contiguous
+	// range of bits, is a port of its own.
+	Buses bool
+}
+
+// verilogKeywords holds the reserved words of Verilog, and the SystemVerilog
+// types most likely to be used as labels, which cannot be used as identifiers.
+var verilogKeywords = map[string]bool{}
+
+func init() {
+	for _, keyword := range strings.Fields(`always and assign automatic begin buf
+		bufif0 bufif1 case casex casez cell cmos config deassign default defparam
+		design disable edge else end endcase endconfig endfunction endgenerate
+		endmodule endprimitive endspecify endtable endtask event for force
+		forever fork function generate genvar highz0 highz1 if ifnone incdir
+		include initial inout input instance integer join large liblist library
+		localparam macromodule medium module nand negedge nmos nor
+		noshowcancelled not notif0 notif1 or output parameter pmos posedge
+		primitive pull0 pull1 pulldown pullup pulsestyle_ondetect
+		pulsestyle_onevent rcmos real realtime reg release repeat rnmos rpmos
+		rtran rtranif0 rtranif1 scalared showcancelled signed small specify
+		specparam strong0 strong1 supply0 supply1 table task time tran tranif0
+		tranif1 tri tri0 tri1 triand trior trireg unsigned use uwire vectored
+		wait wand weak0 weak1 while wire wor xnor xor logic bit byte int`) {
+		verilogKeywords[keyword] = true
+	}
+}
+
+// verilogIdentifier returns a legal Verilog identifier for a label, in which
+// each run of characters that may not appear in an identifier is replaced by
+// a single '_', or dropped at the end of the label, so "addr[3]" becomes
+// "addr_3". Labels starting with a digit, and reserved words, are prefixed by
+// '_'.
+func verilogIdentifier(label string) string {
+	identifier := []byte{}
+	replaced := false
+	for _, r := range label {
+		if r >= 0x80 || !(r == '_' || r == '$' || '0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			replaced = true
+			continue
+		}
+		if replaced && len(identifier) > 0 {
+			identifier = append(identifier, '_')
+		}
+		identifier, replaced = append(identifier, byte(r)), false
+	}
+
+	if len(identifier) == 0 || identifier[0] == '$' || '0' <= identifier[0] && identifier[0] <= '9' || verilogKeywords[string(identifier)] {
+		identifier = append([]byte{'_'}, identifier...)
+	}
+
+	return string(identifier)
+}
+
+// verilogTerm returns a Verilog expression for an implicant, as the product
+// of its literals, such as "a & ~b", or for the ProductOfSums form, the sum of
+// its complemented literals, such as "~a | b". The passed signals give the
+// expression for each input bit.
+func verilogTerm(im Implicant, form Form, signals []string) string {
+	literals := []string{}
+	for bit := im.width - 1; bit >= 0; bit-- {
+		if im.im.xMask.bit(bit) {
+			continue
+		}
+		if im.im.literals.bit(bit) == (form == ProductOfSums) {
+			literals = append(literals, "~"+signals[bit])
+		} else {
+			literals = append(literals, signals[bit])
+		}
+	}
+
+	if form == ProductOfSums {
+		if len(literals) == 0 {
+			return "1'b0"
+		}
+		return strings.Join(literals, " | ")
+	}
+
+	if len(literals) == 0 {
+		return "1'b1"
+	}
+	return strings.Join(literals, " & ")
+}
+
+// WriteVerilog writes the cover held by a Result to w as a synthesizable
+// Verilog module, with an input port for each input and an output port for
+// each output, named from the labels passed to GetMinimumCostCover. Labels
+// are sanitized to legal identifiers, and made unique. Each term shared by
+// the equations of several outputs is assigned to a wire of its own, and each
+// output is given by a continuous assignment of its equation. If the Result's
+// Err is set, it is returned.
+func WriteVerilog(w io.Writer, r Result, options VerilogOptions) error {
+	if r.Err != nil {
+		return r.Err
+	}
+
+	moduleName := options.ModuleName
+	if moduleName == "" {
+		moduleName = "logic_function"
+	}
+
+	used := map[string]bool{}
+	key := func(s string) string { return s }
+	inLabels, outLabels := portLabels(r)
+
+	// declare each port, and find the expression of each of its signals
+	declarations := []string{}
+	inputs := make([]string, r.NumInputs)
+	outputs := make([]string, len(r.Outputs))
+	for _, direction := range []struct {
+		keyword string
+		labels  []string
+		signals []string
+	}{{"input ", inLabels, inputs}, {"output", outLabels, outputs}} {
+		for _, p := range groupPorts(direction.labels, options.Buses) {
+			name := uniqueIdentifier(verilogIdentifier(p.label), used, key)
+			if p.bus {
+				declarations = append(declarations, fmt.Sprintf("%s wire [%d:%d] %s", direction.keyword, p.msb, p.lsb, name))
+			} else {
+				declarations = append(declarations, fmt.Sprintf("%s wire %s", direction.keyword, name))
+			}
+
+			for signal, bit := range p.bits {
+				direction.signals[signal] = name
+				if p.bus {
+					direction.signals[signal] = fmt.Sprintf("%s[%d]", name, bit)
+				}
+			}
+		}
+	}
+
+	// input bit i is the signal at position NumInputs-1-i
+	signals := make([]string, r.NumInputs)
+	for i := range signals {
+		signals[i] = inputs[r.NumInputs-1-i]
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "module %s (\n", verilogIdentifier(moduleName))
+	for i, declaration := range declarations {
+		separator := ","
+		if i == len(declarations)-1 {
+			separator = ""
+		}
+		fmt.Fprintf(&b, "    %s%s\n", declaration, separator)
+	}
+	fmt.Fprintf(&b, ");\n")
+
+	terms, implicants := sharedTerms(r)
+	wires := sharedNames(terms, used, key)
+
+	if len(terms) > 0 {
+		fmt.Fprintf(&b, "\n")
+		for _, term := range terms {
+			fmt.Fprintf(&b, "    wire %s;\n", wires[term])
+		}
+		fmt.Fprintf(&b, "\n")
+		for _, term := range terms {
+			fmt.Fprintf(&b, "    assign %s = %s;\n", wires[term], verilogTerm(implicants[term], term.form, signals))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n")
+	for o, output := range r.Outputs {
+		terms := []string{}
+		for _, im := range output.Implicants {
+			term := verilogTerm(im, output.Form, signals)
+			if wire, ok := wires[sharedTerm{output.Form, im.String()}]; ok {
+				term = wire
+			} else if len(output.Implicants) > 1 && im.LiteralCount() > 1 {
+				term = "(" + term + ")"
+			}
+			terms = append(terms, term)
+		}
+
+		equation := ""
+		switch {
+		case output.Form == ProductOfSums && len(terms) == 0:
+			equation = "1'b1"
+		case output.Form == ProductOfSums:
+			equation = strings.Join(terms, " & ")
+		case len(terms) == 0:
+			equation = "1'b0"
+		default:
+			equation = strings.Join(terms, " | ")
+		}
+
+		fmt.Fprintf(&b, "    assign %s = %s;\n", outputs[o], equation)
+	}
+
+	fmt.Fprintf(&b, "\nendmodule\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,96 @@
+package quinemccluskey
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden fails the test unless got matches the golden file of the passed
+// name in testdata, or if the -update flag is given, rewrites the file.
+func checkGolden(t *testing.T, name string, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s, got:\n%s", path, got)
+	}
+}
+
+// goldenResult returns the cover in the passed form of a function whose
+// labels need sanitizing, with inputs and outputs which are bits of a bus, a
+// term shared by two outputs, and constant outputs.
+func goldenResult(t *testing.T, form Form) Result {
+	t.Helper()
+
+	var f LogicFunction
+	f.Init(false)
+	f.SetForm(form)
+	if err := f.SetNumInputs(3); err != nil {
+		t.Fatal(err)
+	}
+	for _, minterms := range [][]uint64{{2, 3, 5, 7}, {0, 4, 5, 7}, {}, {0, 1, 2, 3, 4, 5, 6, 7}} {
+		if err := f.AddOutput(minterms, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var inLabels InputLabels
+	inLabels.Set(2, "addr[1]")
+	inLabels.Set(1, "addr[0]")
+	inLabels.Set(0, "en")
+	var outLabels OutputLabels
+	for _, label := range []string{"y[1]", "y[0]", "and", "1 out"} {
+		outLabels.Add(label)
+	}
+
+	r := f.GetMinimumCostCover(inLabels, outLabels)
+	if r.Err != nil {
+		t.Fatal(r.Err)
+	}
+	return r
+}
+
+func TestWriteVerilog(t *testing.T) {
+	tests := []struct {
+		name    string
+		form    Form
+		options VerilogOptions
+	}{
+		{"verilog_sop", SumOfProducts, VerilogOptions{}},
+		{"verilog_buses", SumOfProducts, VerilogOptions{ModuleName: "decoder", Buses: true}},
+		{"verilog_pos", ProductOfSums, VerilogOptions{ModuleName: "module"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := WriteVerilog(&b, goldenResult(t, test.form), test.options); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, test.name, b.String())
+		})
+	}
+
+	if err := WriteVerilog(&strings.Builder{}, Result{Err: ErrNoOutputs}, VerilogOptions{}); !errors.Is(err, ErrNoOutputs) {
+		t.Errorf("WriteVerilog() of a Result with an error = %v, want %v", err, ErrNoOutputs)
+	}
+}
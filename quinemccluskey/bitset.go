@@ -0,0 +1,263 @@
+package quinemccluskey
+
+import (
+	"encoding/binary"
+	"math/big"
+	"strings"
+)
+
+// bitset is an arbitrary-length set of bits, used for the terms of functions
+// with any number of inputs. The lowest 64 bits are held in word, so that
+// functions of up to 64 inputs are handled with plain uint64 operations. Any
+// higher bits are packed into ext as little-endian 64-bit words, with no
+// trailing zero words. Holding ext as a string keeps bitset comparable, so
+// that implicants may still be used as map keys.
+type bitset struct {
+	word uint64
+	ext  string
+}
+
+// newBitset returns a bitset holding the bits of v.
+func newBitset(v uint64) bitset {
+	return bitset{word: v}
+}
+
+// unpackExt returns the words packed into an ext string.
+func unpackExt(ext string) []uint64 {
+	words := make([]uint64, len(ext)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64([]byte(ext[i*8 : i*8+8]))
+	}
+	return words
+}
+
+// packExt returns an ext string holding the passed words, with trailing zero
+// words removed.
+func packExt(words []uint64) string {
+	for len(words) > 0 && words[len(words)-1] == 0 {
+		words = words[:len(words)-1]
+	}
+
+	b := make([]byte, len(words)*8)
+	for i, w := range words {
+		binary.LittleEndian.PutUint64(b[i*8:], w)
+	}
+	return string(b)
+}
+
+// combineExt applies op to each pair of words of the ext strings a and b,
+// treating missing words as zero.
+func combineExt(a string, b string, op func(x, y uint64) uint64) string {
+	wa, wb := unpackExt(a), unpackExt(b)
+	n := len(wa)
+	if len(wb) > n {
+		n = len(wb)
+	}
+
+	words := make([]uint64, n)
+	for i := range words {
+		var x, y uint64
+		if i < len(wa) {
+			x = wa[i]
+		}
+		if i < len(wb) {
+			y = wb[i]
+		}
+		words[i] = op(x, y)
+	}
+	return packExt(words)
+}
+
+// lowBits returns a bitset with the lowest n bits set.
+func lowBits(n int) bitset {
+	if n <= 64 {
+		if n == 64 {
+			return bitset{word: ^uint64(0)}
+		}
+		return bitset{word: (1 << n) - 1}
+	}
+
+	words := make([]uint64, (n-1)/64)
+	for i := range words {
+		words[i] = ^uint64(0)
+	}
+	if r := n % 64; r != 0 {
+		words[len(words)-1] = (1 << r) - 1
+	}
+	return bitset{^uint64(0), packExt(words)}
+}
+
+// singleBit returns a bitset with only bit i set.
+func singleBit(i int) bitset {
+	return bitset{}.with(i)
+}
+
+// and returns the bits set in both a and b.
+func (a bitset) and(b bitset) bitset {
+	if a.ext == "" || b.ext == "" {
+		return bitset{word: a.word & b.word}
+	}
+	return bitset{a.word & b.word, combineExt(a.ext, b.ext, func(x, y uint64) uint64 { return x & y })}
+}
+
+// or returns the bits set in either a or b.
+func (a bitset) or(b bitset) bitset {
+	if b.ext == "" {
+		return bitset{a.word | b.word, a.ext}
+	}
+	if a.ext == "" {
+		return bitset{a.word | b.word, b.ext}
+	}
+	return bitset{a.word | b.word, combineExt(a.ext, b.ext, func(x, y uint64) uint64 { return x | y })}
+}
+
+// xor returns the bits set in exactly one of a and b.
+func (a bitset) xor(b bitset) bitset {
+	if a.ext == "" && b.ext == "" {
+		return bitset{word: a.word ^ b.word}
+	}
+	return bitset{a.word ^ b.word, combineExt(a.ext, b.ext, func(x, y uint64) uint64 { return x ^ y })}
+}
+
+// andNot returns the bits set in a but not in b.
+func (a bitset) andNot(b bitset) bitset {
+	if a.ext == "" || b.ext == "" {
+		return bitset{a.word &^ b.word, a.ext}
+	}
+	return bitset{a.word &^ b.word, combineExt(a.ext, b.ext, func(x, y uint64) uint64 { return x &^ y })}
+}
+
+// isZero tests whether no bits are set.
+func (a bitset) isZero() bool {
+	return a.word == 0 && a.ext == ""
+}
+
+// count returns the number of set bits.
+func (a bitset) count() int {
+	n := bitCount(a.word)
+	for _, w := range unpackExt(a.ext) {
+		n += bitCount(w)
+	}
+	return n
+}
+
+// msbPos returns the position of the most significant set bit plus one, or 0
+// if no bits are set.
+func (a bitset) msbPos() int {
+	if a.ext == "" {
+		return msbPos(a.word)
+	}
+	words := unpackExt(a.ext)
+	return 64*len(words) + msbPos(words[len(words)-1])
+}
+
+// bit tests whether bit i is set.
+func (a bitset) bit(i int) bool {
+	if i < 64 {
+		return (a.word>>i)&1 == 1
+	}
+
+	words := unpackExt(a.ext)
+	w := i/64 - 1
+	return w < len(words) && (words[w]>>(i%64))&1 == 1
+}
+
+// with returns a copy of a with bit i set.
+func (a bitset) with(i int) bitset {
+	if i < 64 {
+		return bitset{a.word | 1<<i, a.ext}
+	}
+
+	words := unpackExt(a.ext)
+	for len(words) <= i/64-1 {
+		words = append(words, 0)
+	}
+	words[i/64-1] |= 1 << (i % 64)
+	return bitset{a.word, packExt(words)}
+}
+
+// without returns a copy of a with bit i cleared.
+func (a bitset) without(i int) bitset {
+	if !a.bit(i) {
+		return a
+	}
+	return a.xor(singleBit(i))
+}
+
+// increment returns a bitset holding the value of a plus one.
+func (a bitset) increment() bitset {
+	if a.word != ^uint64(0) {
+		return bitset{a.word + 1, a.ext}
+	}
+
+	words := unpackExt(a.ext)
+	for i := range words {
+		words[i]++
+		if words[i] != 0 {
+			return bitset{0, packExt(words)}
+		}
+	}
+	return bitset{0, packExt(append(words, 1))}
+}
+
+// less orders bitsets by their value as unsigned integers.
+func (a bitset) less(b bitset) bool {
+	if len(a.ext) != len(b.ext) {
+		return len(a.ext) < len(b.ext)
+	}
+
+	wa, wb := unpackExt(a.ext), unpackExt(b.ext)
+	for i := len(wa) - 1; i >= 0; i-- {
+		if wa[i] != wb[i] {
+			return wa[i] < wb[i]
+		}
+	}
+	return a.word < b.word
+}
+
+// binary returns the lowest width bits of a as a string of '1's and '0's,
+// with the most significant bit first.
+func (a bitset) binary(width int) string {
+	var sb strings.Builder
+	for i := width - 1; i >= 0; i-- {
+		if a.bit(i) {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+// decimal returns the value of a in base 10.
+func (a bitset) decimal() string {
+	if a.ext == "" {
+		return new(big.Int).SetUint64(a.word).String()
+	}
+
+	v := new(big.Int)
+	words := unpackExt(a.ext)
+	for i := len(words) - 1; i >= 0; i-- {
+		v.Lsh(v, 64)
+		v.Or(v, new(big.Int).SetUint64(words[i]))
+	}
+	v.Lsh(v, 64)
+	v.Or(v, new(big.Int).SetUint64(a.word))
+	return v.String()
+}
+
+// parseBinary returns the bitset represented by a string of '1's and '0's
+// with the most significant bit first, and whether the string was valid.
+func parseBinary(s string) (bitset, bool) {
+	b := bitset{}
+	for i := 0; i < len(s); i++ {
+		switch s[len(s)-1-i] {
+		case '1':
+			b = b.with(i)
+		case '0':
+		default:
+			return bitset{}, false
+		}
+	}
+	return b, true
+}
@@ -0,0 +1,106 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// bitsetOf returns a bitset with the passed bits set, and the same value as a
+// big.Int.
+func bitsetOf(bits ...int) (bitset, *big.Int) {
+	b, v := bitset{}, new(big.Int)
+	for _, i := range bits {
+		b = b.with(i)
+		v.SetBit(v, i, 1)
+	}
+	return b, v
+}
+
+// checkBitset fails the test unless b holds the value v, in the canonical form
+// with no trailing zero words.
+func checkBitset(t *testing.T, name string, b bitset, v *big.Int) {
+	t.Helper()
+
+	want, _ := parseBinary(v.Text(2))
+	if b != want || b.decimal() != v.String() {
+		t.Errorf("%s = %s, want %s", name, b.decimal(), v)
+	}
+}
+
+func TestBitset(t *testing.T) {
+	sets := [][]int{{}, {0}, {63}, {64}, {0, 64, 130}, {127, 128}, {1, 5, 64, 65, 129}}
+	for _, n := range []int{0, 1, 63, 64, 65, 128, 130} {
+		bits := []int{}
+		for i := 0; i < n; i++ {
+			bits = append(bits, i)
+		}
+		sets = append(sets, bits)
+	}
+
+	for _, bitsA := range sets {
+		a, va := bitsetOf(bitsA...)
+
+		t.Run(fmt.Sprint(bitsA), func(t *testing.T) {
+			if a.count() != len(bitsA) {
+				t.Errorf("count() = %d, want %d", a.count(), len(bitsA))
+			}
+			if a.msbPos() != va.BitLen() {
+				t.Errorf("msbPos() = %d, want %d", a.msbPos(), va.BitLen())
+			}
+			if a.isZero() != (len(bitsA) == 0) {
+				t.Errorf("isZero() = %v", a.isZero())
+			}
+			for i := 0; i < 200; i++ {
+				if a.bit(i) != (va.Bit(i) == 1) {
+					t.Errorf("bit(%d) = %v", i, a.bit(i))
+				}
+			}
+			checkBitset(t, "increment()", a.increment(), new(big.Int).Add(va, big.NewInt(1)))
+			if s := a.binary(140); s != fmt.Sprintf("%0140s", va.Text(2)) {
+				t.Errorf("binary(140) = %s", s)
+			}
+
+			for _, bitsB := range sets {
+				b, vb := bitsetOf(bitsB...)
+				checkBitset(t, fmt.Sprintf("and(%v)", bitsB), a.and(b), new(big.Int).And(va, vb))
+				checkBitset(t, fmt.Sprintf("or(%v)", bitsB), a.or(b), new(big.Int).Or(va, vb))
+				checkBitset(t, fmt.Sprintf("xor(%v)", bitsB), a.xor(b), new(big.Int).Xor(va, vb))
+				checkBitset(t, fmt.Sprintf("andNot(%v)", bitsB), a.andNot(b), new(big.Int).AndNot(va, vb))
+				if a.less(b) != (va.Cmp(vb) < 0) {
+					t.Errorf("less(%v) = %v", bitsB, a.less(b))
+				}
+			}
+		})
+	}
+}
+
+func TestLowBits(t *testing.T) {
+	for _, n := range []int{0, 1, 63, 64, 65, 127, 128, 129, 200} {
+		v := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(n)), big.NewInt(1))
+		checkBitset(t, fmt.Sprintf("lowBits(%d)", n), lowBits(n), v)
+	}
+}
+
+func TestParseBinary(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+		ok   bool
+	}{
+		{"", "0", true},
+		{"0", "0", true},
+		{"101", "5", true},
+		{"1" + fmt.Sprintf("%064d", 0), "18446744073709551616", true},
+		{"0000" + fmt.Sprintf("%064b", uint64(1)), "1", true},
+		{"10x", "0", false},
+		{"12", "0", false},
+	}
+
+	for _, test := range tests {
+		b, ok := parseBinary(test.s)
+		if ok != test.ok || b.decimal() != test.want {
+			t.Errorf("parseBinary(%q) = %s, %v, want %s, %v", test.s, b.decimal(), ok, test.want, test.ok)
+		}
+	}
+}
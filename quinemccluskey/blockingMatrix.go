@@ -0,0 +1,155 @@
+package quinemccluskey
+
+import (
+	"context"
+
+	"golang.org/x/exp/slices"
+)
+
+// blockingRow is a row of the blocking matrix of a minterm of an output, for
+// a cube of the off-set of another or the same output. A cube containing the
+// minterm is kept from intersecting the off-set cube by leaving as a literal
+// any of the inputs in literals, at which the minterm and off-set cube differ,
+// or by excluding the output of the row from its outputs. The output is -1
+// if it is the output of the minterm, which cannot be excluded.
+type blockingRow struct {
+	literals bitset
+	output   int
+}
+
+// hitBy tests whether a cube which keeps the passed inputs as literals, and
+// excludes the passed outputs, is kept from intersecting the off-set cube of
+// the row.
+func (row blockingRow) hitBy(kept bitset, excluded bitset) bool {
+	return !row.literals.and(kept).isZero() || row.output >= 0 && excluded.bit(row.output)
+}
+
+// blockingMatrix finds the primes containing a minterm of an output as the
+// minimal sets of inputs kept as literals and outputs excluded which hit every
+// row of its blocking matrix, as in the EXPAND step of Espresso.
+type blockingMatrix struct {
+	ctx     context.Context
+	rows    []blockingRow
+	width   int
+	minterm bitset
+	full    bitset
+	outputs bitset
+	primes  map[implicant]bool
+}
+
+// critical tests whether each input and output of a set which hits some of
+// the rows of the matrix is the only one of the set hitting at least one of
+// them, which holds for every subset of a minimal hitting set.
+func (b *blockingMatrix) critical(kept bitset, excluded bitset) bool {
+	var criticalKept, criticalExcluded bitset
+	for _, row := range b.rows {
+		literals := row.literals.and(kept)
+		switch count := literals.count(); {
+		case count == 1 && !(row.output >= 0 && excluded.bit(row.output)):
+			criticalKept = criticalKept.or(literals)
+		case count == 0 && row.output >= 0 && excluded.bit(row.output):
+			criticalExcluded = criticalExcluded.with(row.output)
+		}
+	}
+	return criticalKept == kept && criticalExcluded == excluded
+}
+
+// transversals adds to the primes the cube of every minimal hitting set of
+// the rows which contains the passed inputs kept and outputs excluded, and
+// otherwise only the candidate inputs and outputs. Branching on the inputs
+// and outputs of a row not yet hit, with as few as possible, finds each set
+// once.
+func (b *blockingMatrix) transversals(kept bitset, excluded bitset, candidateInputs bitset, candidateOutputs bitset) {
+	if b.ctx.Err() != nil {
+		return
+	}
+
+	chosen := -1
+	size := func(row blockingRow) int {
+		if row.output >= 0 && candidateOutputs.bit(row.output) {
+			return row.literals.and(candidateInputs).count() + 1
+		}
+		return row.literals.and(candidateInputs).count()
+	}
+	for i, row := range b.rows {
+		if !row.hitBy(kept, excluded) && (chosen < 0 || size(row) < size(b.rows[chosen])) {
+			chosen = i
+		}
+	}
+
+	if chosen < 0 {
+		prime := implicant{b.minterm.and(kept), b.full.andNot(kept), b.outputs.andNot(excluded), false}
+		b.primes[prime] = true
+		return
+	}
+
+	// the inputs and outputs of the row are only candidates of the branches
+	// after their own
+	row := b.rows[chosen]
+	inputs := row.literals.and(candidateInputs)
+	candidateInputs = candidateInputs.andNot(inputs)
+	if row.output >= 0 && candidateOutputs.bit(row.output) {
+		candidateOutputs = candidateOutputs.without(row.output)
+		if b.critical(kept, excluded.with(row.output)) {
+			b.transversals(kept, excluded.with(row.output), candidateInputs, candidateOutputs)
+		}
+		candidateOutputs = candidateOutputs.with(row.output)
+	}
+	for i := 0; i < b.width; i++ {
+		if !inputs.bit(i) {
+			continue
+		}
+		if b.critical(kept.with(i), excluded) {
+			b.transversals(kept.with(i), excluded, candidateInputs, candidateOutputs)
+		}
+		candidateInputs = candidateInputs.with(i)
+	}
+}
+
+// offSetPrimes returns the prime implicants of a function in which the
+// off-set of some outputs is given, without enumerating its don't cares. The
+// off-set of each output is found as for Espresso, and the primes containing
+// each minterm are found from its blocking matrix against the off-set, so
+// only primes covering a minterm of one of their outputs are generated. They
+// are returned in canonical order, and passed to the observer. If the context
+// is done, the primes found so far are returned.
+func offSetPrimes(ctx context.Context, minterms [][]bitset, dontCares [][]bitset, offSets [][]bitset, width int, observer Observer) []implicant {
+	e, _ := newEspresso(ctx, minterms, dontCares, offSets, nil, width)
+
+	b := blockingMatrix{
+		ctx:     ctx,
+		width:   width,
+		full:    lowBits(width),
+		outputs: lowBits(len(minterms)),
+		primes:  map[implicant]bool{},
+	}
+	for o := range minterms {
+		for _, minterm := range minterms[o] {
+			if ctx.Err() != nil {
+				break
+			}
+
+			b.minterm, b.rows = minterm, []blockingRow{}
+			for _, r := range e.offSet {
+				literals := r.literals.xor(minterm).andNot(r.xMask)
+				for _, output := range r.outputList() {
+					if output == o {
+						output = -1
+					}
+					b.rows = append(b.rows, blockingRow{literals, output})
+				}
+			}
+			b.transversals(bitset{}, bitset{}, b.full, b.outputs.without(o))
+		}
+	}
+
+	primes := []implicant{}
+	for prime := range b.primes {
+		primes = append(primes, prime)
+	}
+	slices.SortFunc(primes, implicant.less)
+
+	notify(observer, PrimesFoundEvent{exportImplicants(primes, width)})
+
+	return primes
+}
@@ -0,0 +1,161 @@
+package quinemccluskey
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestOffSetPrimes(t *testing.T) {
+	tests := []struct {
+		name     string
+		width    int
+		minterms [][]uint64
+		offSets  [][]uint64
+		want     []string
+	}{
+		{"single minterm", 3, [][]uint64{{7}}, [][]uint64{{0}}, []string{"1xx 1", "x1x 1", "xx1 1"}},
+		{"empty off-set", 2, [][]uint64{{1}}, [][]uint64{{}}, []string{"xx 1"}},
+		{"shared prime", 2, [][]uint64{{3}, {3}}, [][]uint64{{0}, {1}}, []string{"1x 3", "x1 1"}},
+		{"no minterms", 2, [][]uint64{{}, {2}}, [][]uint64{{}, {0}}, []string{"1x 3"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			minterms, dontCares, offSets := [][]bitset{}, [][]bitset{}, [][]bitset{}
+			for o := range test.minterms {
+				minterms, dontCares, offSets = append(minterms, []bitset{}), append(dontCares, []bitset{}), append(offSets, []bitset{})
+				for _, term := range test.minterms[o] {
+					minterms[o] = append(minterms[o], newBitset(term))
+				}
+				for _, term := range test.offSets[o] {
+					offSets[o] = append(offSets[o], newBitset(term))
+				}
+			}
+
+			got := []string{}
+			for _, prime := range offSetPrimes(context.Background(), minterms, dontCares, offSets, test.width, nil) {
+				got = append(got, fmt.Sprintf("%s %s", prime.stringify(test.width), prime.tag.decimal()))
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("offSetPrimes() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOffSetCovers(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 40; i++ {
+		inputs := 2 + rng.Intn(4)
+		outputs := randomOutputs(rng, inputs, 1+rng.Intn(3))
+
+		t.Run(fmt.Sprintf("%d inputs %d outputs", inputs, len(outputs)), func(t *testing.T) {
+			var f LogicFunction
+			f.Init(false)
+			f.SetCoverSolver(BranchAndBoundSolver{})
+			if err := f.SetNumInputs(inputs); err != nil {
+				t.Fatal(err)
+			}
+
+			// the off-set of every other output is given, as the terms which
+			// are neither minterms nor don't cares, so each function is as
+			// it would be given by its don't cares
+			for o, output := range outputs {
+				if o%2 == 1 {
+					if err := f.AddOutput(output.minterms, output.dontCares); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+
+				offSet := []uint64{}
+				for term := uint64(0); term < 1<<inputs; term++ {
+					if !slices.Contains(output.minterms, term) && !slices.Contains(output.dontCares, term) {
+						offSet = append(offSet, term)
+					}
+				}
+				if err := f.AddOutputOffSet(output.minterms, offSet); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{})
+			checkResult(t, r, outputs)
+			if !r.Optimal {
+				t.Errorf("cover is not optimal")
+			}
+
+			// the cover costs the same as that found by listing don't cares
+			want := newTestFunction(t, inputs, outputs)
+			want.SetCoverSolver(BranchAndBoundSolver{})
+			if cost := want.GetMinimumCostCover(InputLabels{}, OutputLabels{}).Cost; !costsEqual(r.Cost, cost) {
+				t.Errorf("cover costs %v, want %v", r.Cost, cost)
+			}
+		})
+	}
+}
+
+func TestOffSetSize(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, inputs := range []int{24, 70} {
+		t.Run(fmt.Sprintf("%d inputs", inputs), func(t *testing.T) {
+			// 8 minterms and 8 terms of the off-set, distinct at random
+			terms := []bitset{}
+			for len(terms) < 16 {
+				term := newBitset(0)
+				for i := 0; i < inputs; i++ {
+					if rng.Intn(2) == 1 {
+						term = term.with(i)
+					}
+				}
+				if !slices.Contains(terms, term) {
+					terms = append(terms, term)
+				}
+			}
+			minterms, offSet := []string{}, []string{}
+			for i, term := range terms {
+				if i < 8 {
+					minterms = append(minterms, term.binary(inputs))
+				} else {
+					offSet = append(offSet, term.binary(inputs))
+				}
+			}
+
+			var f LogicFunction
+			f.Init(false)
+			if err := f.SetNumInputs(inputs); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.AddOutputOffSetTerms(minterms, offSet); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			r := f.GetMinimumCostCoverContext(ctx, InputLabels{}, OutputLabels{})
+			if r.Err != nil {
+				t.Fatal(r.Err)
+			}
+
+			for _, terms := range []struct {
+				terms   []string
+				covered bool
+			}{{minterms, true}, {offSet, false}} {
+				for _, term := range terms.terms {
+					covered := slices.IndexFunc(r.Outputs[0].Implicants, func(im Implicant) bool { return im.Cube().Covers(term) }) >= 0
+					if covered != terms.covered {
+						t.Errorf("term %s is covered %v by\n%s", term, covered, r)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package quinemccluskey
+
+import (
+	"math"
+	"sort"
+)
+
+// coverMatrix is a CoverProblem in the form of index sets, where each row is
+// a row of the problem and each column is a prime.
+type coverMatrix struct {
+	// rowColumns holds, for each row, the columns which cover it.
+	rowColumns []indexSet
+	// columnRows holds, for each column, the rows which it covers.
+	columnRows []indexSet
+	// costs holds the cost of selecting each column.
+	costs []float64
+}
+
+// newCoverMatrix builds a coverMatrix from the passed CoverProblem.
+func newCoverMatrix(problem CoverProblem) coverMatrix {
+	m := coverMatrix{}
+
+	for range problem.Rows {
+		m.rowColumns = append(m.rowColumns, newIndexSet(len(problem.Primes)))
+	}
+
+	for p, cover := range problem.Covers {
+		m.columnRows = append(m.columnRows, newIndexSet(len(problem.Rows)))
+		m.costs = append(m.costs, problem.Cost(p))
+		for _, r := range cover {
+			m.columnRows[p].add(r)
+			m.rowColumns[r].add(p)
+		}
+	}
+
+	return m
+}
+
+// coverState is a node of the branch-and-bound search, holding the rows still
+// to be covered, the columns still available, and the columns chosen so far.
+type coverState struct {
+	rows    indexSet
+	columns indexSet
+	chosen  []int
+	cost    float64
+}
+
+// clone returns a deep copy of the calling coverState.
+func (s coverState) clone() coverState {
+	return coverState{
+		rows:    s.rows.clone(),
+		columns: s.columns.clone(),
+		chosen:  append([]int{}, s.chosen...),
+		cost:    s.cost,
+	}
+}
+
+// branchAndBound searches a coverMatrix for a minimum cost cover.
+type branchAndBound struct {
+	matrix coverMatrix
+	// nodeLimit is the maximum number of branching nodes to explore, or 0 for
+	// no limit.
+	nodeLimit int
+	nodes     int
+	// cancelled reports whether the search has been cancelled.
+	cancelled func() bool
+	// cutOff is set when the search is abandoned due to nodeLimit or
+	// cancellation, or when a row is covered by no column, in which case best
+	// is not proven to be optimal.
+	cutOff   bool
+	best     []int
+	bestCost float64
+	// enumerate is set when every minimum cost cover is to be found, up to
+	// limit covers, or all of them if limit is 0. The covers found with cost
+	// equal to bestCost are held in all.
+	enumerate bool
+	limit     int
+	all       [][]int
+}
+
+// choose adds the column c to the passed coverState, removing it and all of
+// the rows that it covers from the state.
+func (bb *branchAndBound) choose(s *coverState, c int) {
+	s.chosen = append(s.chosen, c)
+	s.cost += bb.matrix.costs[c]
+	s.columns.remove(c)
+	for _, r := range bb.matrix.columnRows[c].indices() {
+		s.rows.remove(r)
+	}
+}
+
+// reduce repeatedly applies essential column extraction, row dominance and
+// column dominance to the passed coverState until none of them make a change.
+// If a row is left with no column to cover it, false is returned.
+func (bb *branchAndBound) reduce(s *coverState) bool {
+	for changed := true; changed; {
+		changed = false
+
+		// a row covered by exactly one column makes that column essential
+		for _, r := range s.rows.indices() {
+			if !s.rows.has(r) {
+				continue
+			}
+
+			columns := bb.matrix.rowColumns[r].intersection(s.columns)
+			switch columns.count() {
+			case 0:
+				return false
+			case 1:
+				bb.choose(s, columns.indices()[0])
+				changed = true
+			}
+		}
+
+		// a row whose columns are a superset of another row's columns is
+		// covered whenever the other row is, so it may be removed
+		rows := s.rows.indices()
+		rowColumns := make([]indexSet, len(rows))
+		for i, r := range rows {
+			rowColumns[i] = bb.matrix.rowColumns[r].intersection(s.columns)
+		}
+		for i := range rows {
+			for j := range rows {
+				if i == j || !s.rows.has(rows[i]) || !s.rows.has(rows[j]) {
+					continue
+				}
+				if rowColumns[i].subsetOf(rowColumns[j]) && (j > i || !rowColumns[j].subsetOf(rowColumns[i])) {
+					s.rows.remove(rows[j])
+					changed = true
+				}
+			}
+		}
+
+		// a column whose rows are a subset of a column of no greater cost may
+		// be removed, along with any column which no longer covers a row.
+		// Removing dominated columns may discard covers of equal cost, so only
+		// columns which cover no rows are removed when enumerating
+		columns := s.columns.indices()
+		columnRows := make([]indexSet, len(columns))
+		for i, c := range columns {
+			columnRows[i] = bb.matrix.columnRows[c].intersection(s.rows)
+			if columnRows[i].count() == 0 {
+				s.columns.remove(c)
+				changed = true
+			}
+		}
+		for i, ci := range columns {
+			for j, cj := range columns {
+				if bb.enumerate || i == j || !s.columns.has(ci) || !s.columns.has(cj) {
+					continue
+				}
+				dominated := columnRows[i].subsetOf(columnRows[j]) && bb.matrix.costs[cj] <= bb.matrix.costs[ci]
+				mutual := columnRows[j].subsetOf(columnRows[i]) && bb.matrix.costs[ci] <= bb.matrix.costs[cj]
+				if dominated && (i > j || !mutual) {
+					s.columns.remove(ci)
+					changed = true
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// lowerBound returns a lower bound on the cost of covering the remaining rows
+// of the passed coverState. A set of rows is found in which no two rows share
+// a column, so each must be covered by a different column, and the cheapest
+// column for each of these rows is summed.
+func (bb *branchAndBound) lowerBound(s coverState) float64 {
+	rows := s.rows.indices()
+	sort.SliceStable(rows, func(i, j int) bool {
+		return bb.matrix.rowColumns[rows[i]].intersection(s.columns).count() < bb.matrix.rowColumns[rows[j]].intersection(s.columns).count()
+	})
+
+	bound := 0.0
+	used := newIndexSet(len(bb.matrix.columnRows))
+	for _, r := range rows {
+		columns := bb.matrix.rowColumns[r].intersection(s.columns)
+		if columns.intersects(used) {
+			continue
+		}
+		used = used.union(columns)
+
+		cheapest := math.Inf(1)
+		for _, c := range columns.indices() {
+			cheapest = math.Min(cheapest, bb.matrix.costs[c])
+		}
+		bound += cheapest
+	}
+
+	return bound
+}
+
+// greedy completes the passed coverState by repeatedly choosing the column
+// which covers the most remaining rows per unit cost, providing an initial
+// upper bound for the search.
+func (bb *branchAndBound) greedy(s coverState) coverState {
+	for s.rows.count() > 0 {
+		best := -1
+		bestRatio := 0.0
+		for _, c := range s.columns.indices() {
+			ratio := float64(bb.matrix.columnRows[c].intersection(s.rows).count()) / bb.matrix.costs[c]
+			if ratio > bestRatio {
+				best = c
+				bestRatio = ratio
+			}
+		}
+		bb.choose(&s, best)
+	}
+
+	return s
+}
+
+// search explores the passed coverState, recording any cover cheaper than the
+// best found so far.
+func (bb *branchAndBound) search(s coverState) {
+	if !bb.reduce(&s) {
+		return
+	}
+
+	if s.rows.count() == 0 {
+		switch {
+		case bb.enumerate && costsEqual(s.cost, bb.bestCost):
+			if bb.limit == 0 || len(bb.all) < bb.limit {
+				bb.all = append(bb.all, s.chosen)
+			}
+		case s.cost < bb.bestCost:
+			bb.best = s.chosen
+			bb.bestCost = s.cost
+			bb.all = [][]int{s.chosen}
+		}
+		return
+	}
+
+	// when enumerating, nodes which may yet reach the best cost are explored
+	bound := s.cost + bb.lowerBound(s)
+	if bound >= bb.bestCost && !(bb.enumerate && costsEqual(bound, bb.bestCost)) {
+		return
+	}
+
+	if (bb.nodeLimit > 0 && bb.nodes >= bb.nodeLimit) || bb.cancelled() {
+		bb.cutOff = true
+		return
+	}
+	bb.nodes++
+
+	// branch on the row covered by the fewest columns
+	branchRow := -1
+	branchColumns := indexSet{}
+	for _, r := range s.rows.indices() {
+		columns := bb.matrix.rowColumns[r].intersection(s.columns)
+		if branchRow == -1 || columns.count() < branchColumns.count() {
+			branchRow = r
+			branchColumns = columns
+		}
+	}
+
+	// each branch chooses one of the row's columns and excludes the columns
+	// chosen by previous branches, so no cover is explored twice
+	excluded := []int{}
+	for _, c := range branchColumns.indices() {
+		child := s.clone()
+		for _, e := range excluded {
+			child.columns.remove(e)
+		}
+		bb.choose(&child, c)
+		bb.search(child)
+		excluded = append(excluded, c)
+	}
+}
+
+// run searches the whole coverMatrix, starting from a greedy cover.
+func (bb *branchAndBound) run() {
+	root := coverState{
+		rows:    newIndexSet(len(bb.matrix.rowColumns)),
+		columns: newIndexSet(len(bb.matrix.columnRows)),
+	}
+	for r := range bb.matrix.rowColumns {
+		root.rows.add(r)
+	}
+	for c := range bb.matrix.columnRows {
+		root.columns.add(c)
+	}
+
+	// a row which no column covers leaves the problem without a cover
+	for _, columns := range bb.matrix.rowColumns {
+		if columns.count() == 0 {
+			bb.best = []int{}
+			bb.cutOff = true
+			return
+		}
+	}
+
+	initial := bb.greedy(root.clone())
+	bb.best = initial.chosen
+	bb.bestCost = initial.cost
+
+	bb.search(root)
+}
+
+// BranchAndBoundSolver solves a CoverProblem using a branch-and-bound search.
+// At each node the problem is reduced by essential columns and row and column
+// dominance, and the node is pruned if a lower bound on its cost is no better
+// than the best cover found so far.
+type BranchAndBoundSolver struct {
+	// NodeLimit is the maximum number of branching nodes to explore, or 0 to
+	// explore the search space completely. If the limit is reached, the best
+	// cover found so far is returned and is not proven to be optimal.
+	NodeLimit int
+}
+
+// SolveCover implements CoverSolver.
+func (solver BranchAndBoundSolver) SolveCover(problem CoverProblem) ([]int, bool) {
+	bb := branchAndBound{
+		matrix:    newCoverMatrix(problem),
+		nodeLimit: solver.NodeLimit,
+		cancelled: problem.cancelled,
+	}
+	bb.run()
+
+	return bb.best, !bb.cutOff
+}
+
+// EnumerateCovers implements CoverEnumerator. Column dominance is not applied,
+// and nodes whose lower bound equals the best cost found so far are explored,
+// so the search is slower than SolveCover.
+func (solver BranchAndBoundSolver) EnumerateCovers(problem CoverProblem, limit int) ([][]int, bool) {
+	bb := branchAndBound{
+		matrix:    newCoverMatrix(problem),
+		nodeLimit: solver.NodeLimit,
+		cancelled: problem.cancelled,
+		enumerate: true,
+		limit:     limit,
+	}
+	bb.run()
+
+	// the search may be cut off before reaching a cover of the best cost
+	if len(bb.all) == 0 {
+		return [][]int{bb.best}, false
+	}
+
+	covers := [][]int{}
+	for _, cover := range bb.all {
+		cover = append([]int{}, cover...)
+		sort.Ints(cover)
+		covers = append(covers, cover)
+	}
+
+	return covers, !bb.cutOff
+}
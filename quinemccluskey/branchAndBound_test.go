@@ -0,0 +1,52 @@
+package quinemccluskey
+
+import (
+	"testing"
+)
+
+func TestBranchAndBoundSolver(t *testing.T) {
+	for _, test := range coverProblemTests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := newTestProblem(test.rows, test.costs, test.covers)
+
+			selected, optimal := BranchAndBoundSolver{}.SolveCover(problem)
+			if cost := selectionCost(t, problem, selected); !costsEqual(cost, test.cost) || !optimal {
+				t.Errorf("SolveCover() = %v with cost %v, optimal %v, want cost %v, optimal", selected, cost, optimal, test.cost)
+			}
+
+			covers, optimal := BranchAndBoundSolver{}.EnumerateCovers(problem, 0)
+			if len(covers) != test.count || !optimal {
+				t.Errorf("EnumerateCovers() = %v, optimal %v, want %d covers, optimal", covers, optimal, test.count)
+			}
+			for _, cover := range covers {
+				if cost := selectionCost(t, problem, cover); !costsEqual(cost, test.cost) {
+					t.Errorf("EnumerateCovers() cover %v has cost %v, want %v", cover, cost, test.cost)
+				}
+			}
+		})
+	}
+}
+
+func TestBranchAndBoundNodeLimit(t *testing.T) {
+	// no row or column of a problem with a column for each pair of rows
+	// dominates another, so it cannot be reduced and must be branched on
+	covers, costs := [][]int{}, []float64{}
+	for r := 0; r < 7; r++ {
+		for s := r + 1; s < 7; s++ {
+			covers = append(covers, []int{r, s})
+			costs = append(costs, 1)
+		}
+	}
+	problem := newTestProblem(7, costs, covers)
+
+	selected, optimal := BranchAndBoundSolver{NodeLimit: 1}.SolveCover(problem)
+	selectionCost(t, problem, selected)
+	if optimal {
+		t.Errorf("SolveCover() with NodeLimit 1 is optimal, want a cover which is not proven optimal")
+	}
+
+	selected, optimal = BranchAndBoundSolver{}.SolveCover(problem)
+	if cost := selectionCost(t, problem, selected); cost != 4 || !optimal {
+		t.Errorf("SolveCover() = %v with cost %v, optimal %v, want cost 4, optimal", selected, cost, optimal)
+	}
+}
@@ -1,25 +1,35 @@
 package quinemccluskey
 
 import (
-	"fmt"
+	"context"
+	"sort"
 
 	"golang.org/x/exp/slices"
 )
 
 type coverTable struct {
+	minterms          [][]bitset
 	primes            []implicant
-	covers            [][][]uint64
-	remainingMinterms [][]uint64
+	covers            [][][]bitset
+	remainingMinterms [][]bitset
+	// essentials records the essential primes removed from the table, each
+	// tagged with a single output for which it is essential, and
+	// essentialMinterms the minterm of that output covered by no other prime.
+	essentials        []implicant
+	essentialMinterms []bitset
 }
 
 // build initialized cover table by copying the passed minterms and primes list
 // into table.remainingMinterms and table.primes respectively, and populating
 // covers with lists of of all the provided minterms covered by the implicant
-// at the same index in primes.
-func (table *coverTable) build(minterms [][]uint64, primes []implicant) {
+// at the same index in primes. If the context is done, the covers are left
+// incomplete.
+func (table *coverTable) build(ctx context.Context, minterms [][]bitset, primes []implicant) {
+	table.minterms = minterms
+
 	// deep copy minterms into table.remainingMinterms
 	for i, output := range minterms {
-		table.remainingMinterms = append(table.remainingMinterms, []uint64{})
+		table.remainingMinterms = append(table.remainingMinterms, []bitset{})
 		for _, minterm := range output {
 			table.remainingMinterms[i] = append(table.remainingMinterms[i], minterm)
 		}
@@ -31,11 +41,14 @@ func (table *coverTable) build(minterms [][]uint64, primes []implicant) {
 
 	// build a list of all of the minterms that each prime covers
 	for p, prime := range table.primes {
-		table.covers = append(table.covers, [][]uint64{})
+		if ctx.Err() != nil {
+			return
+		}
+		table.covers = append(table.covers, [][]bitset{})
 		for o, output := range table.remainingMinterms {
-			table.covers[p] = append(table.covers[p], []uint64{})
+			table.covers[p] = append(table.covers[p], []bitset{})
 			for _, minterm := range output {
-				if (prime.tag>>o)&1 == 1 && prime.covers(minterm) {
+				if prime.tag.bit(o) && prime.covers(minterm) {
 					table.covers[p][o] = append(table.covers[p][o], minterm)
 				}
 			}
@@ -52,7 +65,7 @@ func (table *coverTable) removePrimeAndCovers(prime implicant) {
 	}
 
 	for _, output := range prime.outputList() {
-		nonVolatileCovers := make([]uint64, len(table.covers[primeIndex][output]))
+		nonVolatileCovers := make([]bitset, len(table.covers[primeIndex][output]))
 		copy(nonVolatileCovers, table.covers[primeIndex][output])
 
 		for _, coveredMinterm := range nonVolatileCovers {
@@ -66,7 +79,7 @@ func (table *coverTable) removePrimeAndCovers(prime implicant) {
 
 			// remove minterm from remainingMinterms for the current output
 			for output, _ := range table.remainingMinterms {
-				if (table.primes[primeIndex].tag>>output)&1 == 0 {
+				if !table.primes[primeIndex].tag.bit(output) {
 					continue
 				}
 
@@ -108,8 +121,13 @@ func (table *coverTable) removeEssentialPrimes() []implicant {
 			}
 
 			// add prime to essential primes
-			if !slices.Contains(essentialPrimes, table.primes[essentialPrimeIndex]) {
-				essentialPrimes = append(essentialPrimes, table.primes[essentialPrimeIndex])
+			prime := table.primes[essentialPrimeIndex]
+			if !slices.Contains(essentialPrimes, prime) {
+				essentialPrimes = append(essentialPrimes, prime)
+			}
+			if !table.isEssential(prime, out) {
+				table.essentials = append(table.essentials, implicant{prime.literals, prime.xMask, singleBit(out), false})
+				table.essentialMinterms = append(table.essentialMinterms, minterm)
 			}
 		}
 	}
@@ -122,68 +140,236 @@ func (table *coverTable) removeEssentialPrimes() []implicant {
 	return essentialPrimes
 }
 
+// isEssential tests whether the passed implicant was removed from the table as
+// an essential prime of the passed output.
+func (table *coverTable) isEssential(im implicant, output int) bool {
+	for _, essential := range table.essentials {
+		if essential.equals(im) && essential.tag.bit(output) {
+			return true
+		}
+	}
+	return false
+}
+
+// essentialPrimes returns the essential primes removed from the calling
+// coverTable, once for each output to which they are essential.
+func (table *coverTable) essentialPrimes(implicantDisplayWidth int) []EssentialPrime {
+	essentialPrimes := []EssentialPrime{}
+	for i, essential := range table.essentials {
+		output := essential.outputList()[0]
+		essentialPrimes = append(essentialPrimes, EssentialPrime{Implicant{essential, implicantDisplayWidth}, output, table.essentialMinterms[i].binary(implicantDisplayWidth)})
+	}
+	return essentialPrimes
+}
+
+// problem returns the cyclic core remaining in the calling coverTable as a
+// CoverProblem, with the cost of each prime given by the passed CostModel. A
+// prime is only needed by the outputs whose remaining minterms it covers, so
+// its outputs are restricted to those, and it is costed accordingly.
+func (table *coverTable) problem(model CostModel, implicantDisplayWidth int) CoverProblem {
+	problem := CoverProblem{}
+	tags := make([]bitset, len(table.primes))
+
+	for range table.primes {
+		problem.Covers = append(problem.Covers, []int{})
+	}
+
+	for o, output := range table.remainingMinterms {
+		for _, minterm := range output {
+			for p, cover := range table.covers {
+				if slices.Contains(cover[o], minterm) {
+					problem.Covers[p] = append(problem.Covers[p], len(problem.Rows))
+					tags[p] = tags[p].with(o)
+				}
+			}
+			problem.Rows = append(problem.Rows, CoverRow{o, minterm.binary(implicantDisplayWidth)})
+		}
+	}
+
+	for p, prime := range table.primes {
+		prime.tag = tags[p]
+		problem.Primes = append(problem.Primes, Implicant{prime, implicantDisplayWidth})
+	}
+
+	problem.Cost = coverCostFunction(problem.Primes, model, implicantDisplayWidth)
+
+	return problem
+}
+
 // getMinimumCostCover will solve for a minimum cost cover for all of the
 // implicants contained in the calling coverTable, returning a list of
-// implicants that make up the minimum cost cover.
-func (table *coverTable) getMinimumCostCover(implicantDisplayWidth int, mintermDisplayWidth int, printoutsEnabled bool) []implicant {
-	table.visualize(implicantDisplayWidth, mintermDisplayWidth, printoutsEnabled)
+// implicants that make up the minimum cost cover. Once essential primes have
+// been removed, the remaining cyclic core is solved by the passed CoverSolver
+// using costs from the passed CostModel. The returned boolean reports whether
+// the cover is proven to be of minimum cost. Each step is passed to the
+// observer. If the context is done, the returned cover is incomplete.
+func (table *coverTable) getMinimumCostCover(ctx context.Context, solver CoverSolver, model CostModel, implicantDisplayWidth int, observer Observer) ([]implicant, bool) {
+	if observer != nil {
+		observer.Observe(CoverTableBuiltEvent{table.problem(model, implicantDisplayWidth)})
+	}
 
 	// capture and remove essential prime implicants from the coverTable
 	minimumCover := table.removeEssentialPrimes()
-	visualizeHeading("ESSENTIAL PRIMES REMOVED", printoutsEnabled)
-
-	// get the total number of remaining minterms across all outputs
-	totalRemainingMinterms := 0
-	for _, output := range table.remainingMinterms {
-		totalRemainingMinterms += len(output)
-	}
-
-	table.visualize(implicantDisplayWidth, mintermDisplayWidth, printoutsEnabled)
-
-	// capture and remove primes iteratively until all minterms are covered
-	for totalRemainingMinterms > 0 {
-		// pL is the set of remaining primes which cover the greatest number
-		// of minterms
-		pLIndices := []int{}
-		mostCoveredMinterms := 0
-		for i, cover := range table.covers {
-			totalMintermsCovered := 0
-			for _, output := range cover {
-				totalMintermsCovered += len(output)
-			}
-			if totalMintermsCovered > mostCoveredMinterms {
-				mostCoveredMinterms = totalMintermsCovered
-				pLIndices = []int{i}
-			} else if totalMintermsCovered == mostCoveredMinterms {
-				pLIndices = append(pLIndices, i)
+	problem := table.problem(model, implicantDisplayWidth)
+	notify(observer, EssentialPrimesRemovedEvent{table.essentialPrimes(implicantDisplayWidth), problem})
+
+	// a cover made up of only essential primes is necessarily minimal
+	if len(problem.Rows) == 0 {
+		return pruneOutputs(minimumCover, table.minterms), true
+	}
+
+	problem.Context = ctx
+	selected, optimal := solver.SolveCover(problem)
+	if ctx.Err() != nil {
+		return minimumCover, false
+	}
+
+	// capture the selected primes before their indices are invalidated by
+	// removing them from the coverTable
+	cover := []implicant{}
+	for _, p := range selected {
+		cover = append(cover, table.primes[p])
+	}
+
+	// capture and remove all occurances of the selected prime implicants and
+	// the minterms that they cover from the coverTable
+	for _, prime := range cover {
+		table.removePrimeAndCovers(prime)
+		minimumCover = append(minimumCover, prime)
+
+		// progress only needs the size of the table, not the table itself
+		if p, ok := progressOnly(observer); ok {
+			rows := 0
+			for _, output := range table.remainingMinterms {
+				rows += len(output)
 			}
+			p.implicantSelected(len(table.primes), rows)
+		} else if observer != nil {
+			observer.Observe(ImplicantSelectedEvent{Implicant{prime, implicantDisplayWidth}, table.problem(model, implicantDisplayWidth)})
 		}
+	}
 
-		// pI is the first occurance from the set of primes with the fewest
-		// literals (most xMask bits) in pL
-		pI := table.primes[pLIndices[0]]
-		for _, index := range pLIndices[1:] {
-			fmt.Println("checking", table.primes[index])
-			if bitCount(table.primes[index].xMask) > bitCount(pI.xMask) {
-				fmt.Println("picked")
-				pI = table.primes[index]
-			}
+	// shared primes may not be needed by every output in their tag
+	return pruneOutputs(minimumCover, table.minterms), optimal
+}
+
+// getAllMinimumCostCovers will find every minimum cost cover of the
+// implicants contained in the calling coverTable, up to limit covers, or all
+// of them if limit is 0. Once essential primes have been removed, the
+// remaining cyclic core is enumerated by the passed CoverEnumerator using
+// costs from the passed CostModel. Covers which are no longer of minimum cost
+// once their outputs have been pruned, or which are pruned to the same cover
+// as another, are dropped before the limit is applied. The returned boolean
+// reports whether the covers are proven to be of minimum cost. Each step is
+// passed to the observer. If the context is done, the returned covers are
+// incomplete.
+func (table *coverTable) getAllMinimumCostCovers(ctx context.Context, enumerator CoverEnumerator, limit int, model CostModel, implicantDisplayWidth int, observer Observer) ([][]implicant, bool) {
+	if observer != nil {
+		observer.Observe(CoverTableBuiltEvent{table.problem(model, implicantDisplayWidth)})
+	}
+
+	// capture and remove essential prime implicants from the coverTable
+	essentialPrimes := table.removeEssentialPrimes()
+	problem := table.problem(model, implicantDisplayWidth)
+	notify(observer, EssentialPrimesRemovedEvent{table.essentialPrimes(implicantDisplayWidth), problem})
+
+	// a cover made up of only essential primes is the only minimal cover
+	if len(problem.Rows) == 0 {
+		return [][]implicant{pruneOutputs(essentialPrimes, table.minterms)}, true
+	}
+
+	// every selection is enumerated, as the limit applies to the covers which
+	// remain of minimum cost once pruned
+	problem.Context = ctx
+	selections, optimal := enumerator.EnumerateCovers(problem, 0)
+	if ctx.Err() != nil {
+		return [][]implicant{essentialPrimes}, false
+	}
+
+	// each cover is compared by cost and then by number of literals
+	covers := [][]implicant{}
+	costs, literals := []float64{}, []int{}
+	best := -1
+	for _, selected := range selections {
+		cover := append([]implicant{}, essentialPrimes...)
+		for _, p := range selected {
+			cover = append(cover, table.primes[p])
 		}
+		cover = pruneOutputs(cover, table.minterms)
+		slices.SortFunc(cover, implicant.less)
 
-		// capture and remove all occurances of the selected prime implicant
-		// and the minterms that it covers from the coverTable
-		table.removePrimeAndCovers(pI)
-		minimumCover = append(minimumCover, pI)
+		coverLiterals := 0
+		for _, im := range cover {
+			coverLiterals += Implicant{im, implicantDisplayWidth}.LiteralCount()
+		}
 
-		// update the count of remaining minterms across all outputs
-		totalRemainingMinterms = 0
-		for _, output := range table.remainingMinterms {
-			totalRemainingMinterms += len(output)
+		covers = append(covers, cover)
+		costs = append(costs, totalCost(cover, model, implicantDisplayWidth))
+		literals = append(literals, coverLiterals)
+
+		i := len(covers) - 1
+		if best == -1 || (costsEqual(costs[i], costs[best]) && literals[i] < literals[best]) || (!costsEqual(costs[i], costs[best]) && costs[i] < costs[best]) {
+			best = i
 		}
+	}
 
-		visualizeHeading(pI.stringify(implicantDisplayWidth)+" REMOVED", printoutsEnabled)
-		table.visualize(implicantDisplayWidth, mintermDisplayWidth, printoutsEnabled)
+	// distinct selections may be pruned to the same cover
+	minimumCovers := [][]implicant{}
+	for i, cover := range covers {
+		if limit > 0 && len(minimumCovers) == limit {
+			break
+		}
+		duplicate := slices.IndexFunc(minimumCovers, func(c []implicant) bool { return slices.Equal(c, cover) }) >= 0
+		if costsEqual(costs[i], costs[best]) && literals[i] == literals[best] && !duplicate {
+			notify(observer, MinimumCoverFoundEvent{len(minimumCovers), exportImplicants(cover, implicantDisplayWidth), len(table.minterms)})
+			minimumCovers = append(minimumCovers, cover)
+		}
+	}
+
+	return minimumCovers, optimal
+}
+
+// pruneOutputs removes outputs from the tags of the implicants of a cover
+// where every minterm of that output covered by the implicant is also covered
+// by another implicant of the cover. Implicants left with no outputs are
+// dropped. Implicants with the fewest xMask bits are pruned first.
+func pruneOutputs(cover []implicant, minterms [][]bitset) []implicant {
+	pruned := make([]implicant, len(cover))
+	copy(pruned, cover)
+	sort.SliceStable(pruned, func(i, j int) bool {
+		return pruned[i].xMask.count() < pruned[j].xMask.count()
+	})
+
+	for i, im := range pruned {
+	NEXT_OUTPUT:
+		for _, output := range im.outputList() {
+			for _, minterm := range minterms[output] {
+				if !im.covers(minterm) {
+					continue
+				}
+
+				coveredElsewhere := false
+				for j, other := range pruned {
+					if j != i && other.tag.bit(output) && other.covers(minterm) {
+						coveredElsewhere = true
+						break
+					}
+				}
+				if !coveredElsewhere {
+					continue NEXT_OUTPUT
+				}
+			}
+
+			pruned[i].tag = pruned[i].tag.without(output)
+		}
+	}
+
+	nonEmpty := []implicant{}
+	for _, im := range pruned {
+		if !im.tag.isZero() {
+			nonEmpty = append(nonEmpty, im)
+		}
 	}
 
-	return minimumCover
+	return nonEmpty
 }
@@ -0,0 +1,614 @@
+package quinemccluskey
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"golang.org/x/exp/slices"
+)
+
+// irredundantNodeLimit bounds the branch-and-bound search used to select an
+// irredundant subset of a cover, keeping each Espresso iteration fast.
+const irredundantNodeLimit = 1000
+
+// cofactorVariable returns the cofactor of a cover with respect to the input v
+// taking the passed value. v is a mask with a single bit set.
+func cofactorVariable(cover []implicant, v bitset, value bool) []implicant {
+	result := []implicant{}
+	for _, c := range cover {
+		if c.xMask.and(v).isZero() && !c.literals.and(v).isZero() != value {
+			continue
+		}
+		result = append(result, implicant{c.literals.andNot(v), c.xMask.or(v), c.tag, false})
+	}
+	return result
+}
+
+// cofactorCover returns the cofactor of a cover with respect to the cube p.
+func cofactorCover(cover []implicant, p implicant, full bitset) []implicant {
+	result := []implicant{}
+	for _, c := range cover {
+		if c.intersects(p) {
+			result = append(result, c.cofactor(p, full))
+		}
+	}
+	return result
+}
+
+// splittingVariable returns the input of a cover to split on when recursing,
+// along with whether the cover contains a universal cube. Inputs which appear
+// as a literal of both polarities are preferred, and of those, the input which
+// appears as a literal in the most cubes.
+func splittingVariable(cover []implicant, full bitset) (bitset, bool) {
+	var ones, zeros bitset
+	counts := make([]int, full.msbPos())
+	for _, c := range cover {
+		fixed := full.andNot(c.xMask)
+		if fixed.isZero() {
+			return bitset{}, true
+		}
+		ones = ones.or(fixed.and(c.literals))
+		zeros = zeros.or(fixed.andNot(c.literals))
+		for i := range counts {
+			if fixed.bit(i) {
+				counts[i]++
+			}
+		}
+	}
+
+	candidates := ones.and(zeros)
+	if candidates.isZero() {
+		candidates = ones.or(zeros)
+	}
+
+	best := -1
+	for i := range counts {
+		if candidates.bit(i) && (best == -1 || counts[i] > counts[best]) {
+			best = i
+		}
+	}
+
+	return singleBit(best), false
+}
+
+// tautology tests whether a cover includes every minterm of the inputs in
+// full, using the unate recursive paradigm.
+func tautology(cover []implicant, full bitset) bool {
+	if len(cover) == 0 {
+		return false
+	}
+
+	var ones, zeros bitset
+	for _, c := range cover {
+		fixed := full.andNot(c.xMask)
+		if fixed.isZero() {
+			return true
+		}
+		ones = ones.or(fixed.and(c.literals))
+		zeros = zeros.or(fixed.andNot(c.literals))
+	}
+
+	// a cover which is unate in an input is a tautology only if the cubes
+	// which do not depend on that input are
+	if unate := ones.xor(zeros); !unate.isZero() {
+		reduced := []implicant{}
+		for _, c := range cover {
+			if full.andNot(c.xMask).and(unate).isZero() {
+				reduced = append(reduced, c)
+			}
+		}
+		return tautology(reduced, full)
+	}
+
+	v, _ := splittingVariable(cover, full)
+	return tautology(cofactorVariable(cover, v, false), full) && tautology(cofactorVariable(cover, v, true), full)
+}
+
+// complementCover returns a cover of the minterms of the inputs in full which
+// are not covered by the passed cover, using the unate recursive paradigm.
+func complementCover(cover []implicant, full bitset) []implicant {
+	if len(cover) == 0 {
+		return []implicant{{bitset{}, full, bitset{}, false}}
+	}
+
+	v, universal := splittingVariable(cover, full)
+	if universal {
+		return []implicant{}
+	}
+
+	// apply De Morgan's law to a single cube
+	if len(cover) == 1 {
+		result := []implicant{}
+		fixed := full.andNot(cover[0].xMask)
+		for i := 0; i < full.msbPos(); i++ {
+			if fixed.bit(i) {
+				bit := singleBit(i)
+				result = append(result, implicant{bit.andNot(cover[0].literals), full.andNot(bit), bitset{}, false})
+			}
+		}
+		return result
+	}
+
+	complement0 := complementCover(cofactorVariable(cover, v, false), full)
+	complement1 := complementCover(cofactorVariable(cover, v, true), full)
+
+	return mergeComplements(complement0, complement1, v)
+}
+
+// complementOutputs returns a cover of the pairs of a minterm of the inputs in
+// full and one of the passed outputs which are not covered by a cube of the
+// passed cover applying to that output, using the unate recursive paradigm.
+// The tag of each cube of the result holds the outputs it applies to.
+func complementOutputs(cover []implicant, full bitset, outputs bitset) []implicant {
+	// an output with a universal cube has no complement
+	for _, c := range cover {
+		if full.andNot(c.xMask).isZero() {
+			outputs = outputs.andNot(c.tag)
+		}
+	}
+	if outputs.isZero() {
+		return []implicant{}
+	}
+
+	remaining := []implicant{}
+	for _, c := range cover {
+		if tag := c.tag.and(outputs); !tag.isZero() {
+			remaining = append(remaining, implicant{c.literals, c.xMask, tag, false})
+		}
+	}
+	if len(remaining) == 0 {
+		return []implicant{{bitset{}, full, outputs, false}}
+	}
+
+	// apply De Morgan's law to a single cube applying to every output
+	if len(remaining) == 1 && remaining[0].tag == outputs {
+		result := []implicant{}
+		for _, c := range complementCover(remaining, full) {
+			c.tag = outputs
+			result = append(result, c)
+		}
+		return result
+	}
+
+	v, _ := splittingVariable(remaining, full)
+	complement0 := complementOutputs(cofactorVariable(remaining, v, false), full, outputs)
+	complement1 := complementOutputs(cofactorVariable(remaining, v, true), full, outputs)
+
+	return mergeComplements(complement0, complement1, v)
+}
+
+// mergeComplements returns the complement of a cover from the complements of
+// its cofactors with respect to the input v taking the values 0 and 1. Cubes
+// found in both halves of the complement do not depend on v, and are indexed
+// to find them without comparing every pair of cubes.
+func mergeComplements(complement0 []implicant, complement1 []implicant, v bitset) []implicant {
+	shared := map[implicant]bool{}
+	for _, c := range complement1 {
+		shared[c] = true
+	}
+
+	result := []implicant{}
+	for _, c := range complement0 {
+		if shared[c] {
+			delete(shared, c)
+			result = append(result, c)
+			continue
+		}
+		result = append(result, implicant{c.literals, c.xMask.andNot(v), c.tag, false})
+	}
+	for _, c := range complement1 {
+		if shared[c] {
+			result = append(result, implicant{c.literals.or(v), c.xMask.andNot(v), c.tag, false})
+		}
+	}
+
+	return result
+}
+
+// espresso holds the don't care set and off-set of a LogicFunction for use by
+// the steps of the Espresso heuristic minimization loop. Cubes are represented
+// as implicants, with the tag describing the outputs a cube applies to.
+type espresso struct {
+	ctx       context.Context
+	model     CostModel
+	width     int
+	full      bitset
+	nOutputs  int
+	onSet     [][]bitset
+	dontCares []implicant
+	offSet    []implicant
+}
+
+// newEspresso builds the don't care set and off-set for the passed minterms
+// and don't cares of each output, and returns them along with an initial
+// cover of the on-set made up of one cube per minterm. Where the off-set of an
+// output is given, its don't care set is instead the complement of its
+// minterms and off-set. Terms shared between outputs are merged into a single
+// cube, and each complement is found once for all of the outputs it applies
+// to.
+func newEspresso(ctx context.Context, minterms [][]bitset, dontCares [][]bitset, offSets [][]bitset, model CostModel, width int) (espresso, []implicant) {
+	e := espresso{
+		ctx:      ctx,
+		model:    model,
+		width:    width,
+		full:     lowBits(width),
+		nOutputs: len(minterms),
+		onSet:    minterms,
+	}
+
+	// merge adds a term of output o to a set of cubes, indexed by their
+	// literals
+	merge := func(cubes []implicant, index map[bitset]int, term bitset, o int) []implicant {
+		if i, ok := index[term]; ok {
+			cubes[i].tag = cubes[i].tag.with(o)
+			return cubes
+		}
+		index[term] = len(cubes)
+		return append(cubes, implicant{term, bitset{}, singleBit(o), false})
+	}
+
+	onSet, onIndex := []implicant{}, map[bitset]int{}
+	careSet, careIndex := []implicant{}, map[bitset]int{}
+	dontCareIndex, offSetIndex := map[bitset]int{}, map[bitset]int{}
+	var given, implicit bitset
+	for o := range minterms {
+		for _, minterm := range minterms[o] {
+			onSet = merge(onSet, onIndex, minterm, o)
+			careSet = merge(careSet, careIndex, minterm, o)
+		}
+
+		if offSets[o] != nil {
+			given = given.with(o)
+			for _, term := range offSets[o] {
+				careSet = merge(careSet, careIndex, term, o)
+				e.offSet = merge(e.offSet, offSetIndex, term, o)
+			}
+			continue
+		}
+
+		implicit = implicit.with(o)
+		for _, dontCare := range dontCares[o] {
+			careSet = merge(careSet, careIndex, dontCare, o)
+			e.dontCares = merge(e.dontCares, dontCareIndex, dontCare, o)
+		}
+	}
+
+	// the off-set of the outputs whose off-set is not given is the complement
+	// of their minterms and don't cares, and the don't care set of the others
+	// the complement of their minterms and off-set
+	if !implicit.isZero() {
+		e.offSet = append(e.offSet, complementOutputs(careSet, e.full, implicit)...)
+	}
+	if !given.isZero() {
+		e.dontCares = append(e.dontCares, complementOutputs(careSet, e.full, given)...)
+	}
+
+	return e, onSet
+}
+
+// valid tests whether the passed cube does not intersect the off-set of any
+// of its outputs.
+func (e *espresso) valid(c implicant) bool {
+	for _, r := range e.offSet {
+		if !r.tag.and(c.tag).isZero() && c.intersects(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// outputCofactor returns the cofactor with respect to c of the cubes of the
+// cover, excluding the cube at index skip, and of the don't care set which
+// apply to output o.
+func (e *espresso) outputCofactor(cover []implicant, skip int, c implicant, o int) []implicant {
+	others := []implicant{}
+	for i, d := range cover {
+		if i != skip && d.tag.bit(o) {
+			others = append(others, d)
+		}
+	}
+	for _, d := range e.dontCares {
+		if d.tag.bit(o) {
+			others = append(others, d)
+		}
+	}
+	return cofactorCover(others, c, e.full)
+}
+
+// expandCube raises the inputs of c to 'x' for as long as c remains valid,
+// followed by its outputs. At each step the input is chosen which results in
+// the cube containing the most cubes of the cover, with ties broken by the
+// number of cubes of the cover with the opposite literal.
+func (e *espresso) expandCube(c implicant, cover []implicant) implicant {
+	opposite := make([]int, e.width)
+	for _, d := range cover {
+		differing := c.literals.xor(d.literals).andNot(c.xMask.or(d.xMask))
+		for i := range opposite {
+			if differing.bit(i) {
+				opposite[i]++
+			}
+		}
+	}
+
+	// inputs which cannot be raised remain so as the cube grows
+	candidates := e.full.andNot(c.xMask)
+	for !candidates.isZero() {
+		best := implicant{}
+		bestInput, bestContained := -1, -1
+		for i := 0; i < e.width; i++ {
+			if !candidates.bit(i) {
+				continue
+			}
+
+			bit := singleBit(i)
+			raised := implicant{c.literals.andNot(bit), c.xMask.or(bit), c.tag, false}
+			if !e.valid(raised) {
+				candidates = candidates.andNot(bit)
+				continue
+			}
+
+			contained := 0
+			for _, d := range cover {
+				if raised.contains(d) && d.tag.andNot(raised.tag).isZero() {
+					contained++
+				}
+			}
+
+			if contained > bestContained || (contained == bestContained && opposite[i] > opposite[bestInput]) {
+				best, bestInput, bestContained = raised, i, contained
+			}
+		}
+
+		if bestInput == -1 {
+			break
+		}
+		c = best
+		candidates = candidates.without(bestInput)
+	}
+
+	// only raise outputs for which c covers part of the on-set
+	for o := 0; o < e.nOutputs; o++ {
+		if c.tag.bit(o) {
+			continue
+		}
+		raised := implicant{c.literals, c.xMask, c.tag.with(o), false}
+		if !e.valid(raised) {
+			continue
+		}
+		for _, d := range cover {
+			if d.tag.bit(o) && c.intersects(d) {
+				c = raised
+				break
+			}
+		}
+	}
+
+	return c
+}
+
+// expand replaces each cube of the cover with a prime implicant containing it,
+// dropping any cube contained in a previously expanded cube. Larger cubes are
+// expanded first. Once the context is done, the remaining cubes are kept as
+// they are.
+func (e *espresso) expand(cover []implicant) []implicant {
+	order := make([]implicant, len(cover))
+	copy(order, cover)
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].xMask.count() > order[j].xMask.count()
+	})
+
+	covered := make([]bool, len(order))
+	expanded := []implicant{}
+	for i, c := range order {
+		if covered[i] {
+			continue
+		}
+		if e.ctx.Err() != nil {
+			expanded = append(expanded, c)
+			continue
+		}
+
+		c = e.expandCube(c, order)
+		for j, d := range order {
+			if c.contains(d) && d.tag.andNot(c.tag).isZero() {
+				covered[j] = true
+			}
+		}
+
+		expanded = append(expanded, c)
+	}
+
+	return expanded
+}
+
+// irredundant selects a minimum subset of the cubes of the cover which still
+// covers every minterm of the on-set, solving the selection as a CoverProblem
+// in which minterms covered by the same cubes share a single row. Redundant
+// outputs are then removed from the selected cubes using pruneOutputs.
+func (e *espresso) irredundant(cover []implicant) []implicant {
+	problem := CoverProblem{Covers: make([][]int, len(cover)), Context: e.ctx}
+	for _, c := range cover {
+		problem.Primes = append(problem.Primes, Implicant{c, e.width})
+	}
+
+	rows := map[string]bool{}
+	for o, output := range e.onSet {
+		for _, minterm := range output {
+			columns := newIndexSet(len(cover))
+			for i, c := range cover {
+				if c.tag.bit(o) && c.covers(minterm) {
+					columns.add(i)
+				}
+			}
+
+			key := fmt.Sprint(columns)
+			if rows[key] {
+				continue
+			}
+			rows[key] = true
+
+			for _, i := range columns.indices() {
+				problem.Covers[i] = append(problem.Covers[i], len(problem.Rows))
+			}
+			problem.Rows = append(problem.Rows, CoverRow{o, minterm.binary(e.width)})
+		}
+	}
+
+	problem.Cost = coverCostFunction(problem.Primes, e.model, e.width)
+
+	selected, _ := BranchAndBoundSolver{NodeLimit: irredundantNodeLimit}.SolveCover(problem)
+
+	irredundantCover := []implicant{}
+	for _, i := range selected {
+		irredundantCover = append(irredundantCover, cover[i])
+	}
+	return pruneOutputs(irredundantCover, e.onSet)
+}
+
+// reduceCube returns the smallest cube contained in the cube at index i of the
+// cover which, together with the rest of the cover and the don't care set,
+// still covers the same minterms for each output. Outputs for which the cube
+// is redundant are removed from its tag.
+func (e *espresso) reduceCube(cover []implicant, i int) implicant {
+	c := cover[i]
+	reduced := implicant{}
+	found := false
+
+	for _, o := range c.outputList() {
+		uncovered := complementCover(e.outputCofactor(cover, i, c, o), e.full)
+		if len(uncovered) == 0 {
+			continue
+		}
+
+		for _, u := range uncovered {
+			u = c.intersection(u)
+			u.tag = singleBit(o)
+			if found {
+				reduced = reduced.supercube(u)
+			} else {
+				reduced = u
+				found = true
+			}
+		}
+	}
+
+	return reduced
+}
+
+// reduce replaces each cube of the cover with the smallest cube that still
+// results in a cover, so that a subsequent expand may find different primes.
+// Larger cubes are reduced first.
+func (e *espresso) reduce(cover []implicant) []implicant {
+	cover = append([]implicant{}, cover...)
+
+	order := make([]int, len(cover))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return cover[order[i]].xMask.count() > cover[order[j]].xMask.count()
+	})
+
+	for _, i := range order {
+		cover[i] = e.reduceCube(cover, i)
+	}
+
+	reducedCover := []implicant{}
+	for _, c := range cover {
+		if !c.tag.isZero() {
+			reducedCover = append(reducedCover, c)
+		}
+	}
+
+	return reducedCover
+}
+
+// lastGasp reduces every cube of the cover independently of the others, then
+// expands the reduced cubes, keeping any expansion which contains at least two
+// reduced cubes. If any are found they are added to the cover, and the
+// irredundant result is returned.
+func (e *espresso) lastGasp(cover []implicant) []implicant {
+	reduced := []implicant{}
+	for i := range cover {
+		if c := e.reduceCube(cover, i); !c.tag.isZero() {
+			reduced = append(reduced, c)
+		}
+	}
+
+	additions := []implicant{}
+	for _, c := range reduced {
+		c = e.expandCube(c, reduced)
+
+		contained := 0
+		for _, d := range reduced {
+			if c.contains(d) && d.tag.andNot(c.tag).isZero() {
+				contained++
+			}
+		}
+
+		if contained >= 2 && !slices.Contains(additions, c) {
+			additions = append(additions, c)
+		}
+	}
+
+	if len(additions) == 0 {
+		return cover
+	}
+
+	return e.irredundant(append(append([]implicant{}, cover...), additions...))
+}
+
+// cheaper tests whether cover1 costs less than cover2 under the CostModel, or
+// costs the same and has fewer literals.
+func (e *espresso) cheaper(cover1 []implicant, cover2 []implicant) bool {
+	cost1, cost2 := totalCost(cover1, e.model, e.width), totalCost(cover2, e.model, e.width)
+	if !costsEqual(cost1, cost2) {
+		return cost1 < cost2
+	}
+
+	literals1, literals2 := 0, 0
+	for _, c := range cover1 {
+		literals1 += e.width - c.xMask.count()
+	}
+	for _, c := range cover2 {
+		literals2 += e.width - c.xMask.count()
+	}
+
+	return literals1 < literals2
+}
+
+// espressoCover heuristically minimizes the passed minterms and don't cares of
+// each output, or minterms and off-set where it is given, using the Espresso
+// loop, returning the resulting cover. The cover is made up of prime
+// implicants and is irredundant, but is not guaranteed to be of minimum cost
+// under the passed CostModel. The initial cover and each improvement are
+// passed to the observer. The loop stops early once the context is done.
+func espressoCover(ctx context.Context, minterms [][]bitset, dontCares [][]bitset, offSets [][]bitset, model CostModel, implicantDisplayWidth int, observer Observer) []implicant {
+	e, cover := newEspresso(ctx, minterms, dontCares, offSets, model, implicantDisplayWidth)
+
+	cover = e.irredundant(e.expand(cover))
+	notify(observer, EspressoStepEvent{EspressoInitialCover, exportImplicants(cover, implicantDisplayWidth), e.nOutputs})
+
+	for ctx.Err() == nil {
+		// iterate reduce, expand, and irredundant until the cost stops
+		// improving
+		for ctx.Err() == nil {
+			next := e.irredundant(e.expand(e.reduce(cover)))
+			if !e.cheaper(next, cover) {
+				break
+			}
+			cover = next
+			notify(observer, EspressoStepEvent{EspressoReduceExpandIrredundant, exportImplicants(cover, implicantDisplayWidth), e.nOutputs})
+		}
+
+		next := e.lastGasp(cover)
+		if !e.cheaper(next, cover) {
+			break
+		}
+		cover = next
+		notify(observer, EspressoStepEvent{EspressoLastGasp, exportImplicants(cover, implicantDisplayWidth), e.nOutputs})
+	}
+
+	return cover
+}
@@ -0,0 +1,107 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestComplementOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		cubes   []string
+		tags    []uint64
+		outputs uint64
+	}{
+		{"empty cover", nil, nil, 0b11},
+		{"universal cube", []string{"xxx"}, []uint64{0b01}, 0b11},
+		{"single cube", []string{"1x0"}, []uint64{0b11}, 0b11},
+		{"disjoint outputs", []string{"1xx", "0x1"}, []uint64{0b01, 0b10}, 0b11},
+		{"shared cubes", []string{"11x", "x01", "000", "1xx"}, []uint64{0b11, 0b01, 0b10, 0b100}, 0b111},
+		{"excluded output", []string{"01x", "1x1"}, []uint64{0b01, 0b10}, 0b10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cover := []implicant{}
+			for i, s := range test.cubes {
+				c, err := ParseCube(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				cover = append(cover, implicant{c.im.literals, c.im.xMask, newBitset(test.tags[i]), false})
+			}
+
+			complement := complementOutputs(cover, lowBits(3), newBitset(test.outputs))
+			for term := uint64(0); term < 8; term++ {
+				for o := 0; o < 3; o++ {
+					covered := func(cubes []implicant) bool {
+						for _, c := range cubes {
+							if c.tag.bit(o) && c.covers(newBitset(term)) {
+								return true
+							}
+						}
+						return false
+					}
+
+					want := test.outputs>>o&1 == 1 && !covered(cover)
+					if got := covered(complement); got != want {
+						t.Errorf("complement covers term %d of output %d is %v, want %v", term, o, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEspresso(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20; i++ {
+		inputs := 3 + rng.Intn(5)
+		outputs := randomOutputs(rng, inputs, 1+rng.Intn(4))
+
+		t.Run(fmt.Sprintf("%d inputs %d outputs", inputs, len(outputs)), func(t *testing.T) {
+			f := newTestFunction(t, inputs, outputs)
+			f.SetEngine(Espresso)
+			checkResult(t, f.GetMinimumCostCover(InputLabels{}, OutputLabels{}), outputs)
+		})
+	}
+}
+
+func TestEspressoManyTerms(t *testing.T) {
+	outputs := randomOutputs(rand.New(rand.NewSource(1)), 10, 4)
+
+	f := newTestFunction(t, 10, outputs)
+	f.SetEngine(Espresso)
+	checkResult(t, f.GetMinimumCostCover(InputLabels{}, OutputLabels{}), outputs)
+}
+
+// stringCost is a CostModel giving the cost of each implicant by its string.
+type stringCost map[string]float64
+
+func (model stringCost) ImplicantCost(im Implicant) float64 {
+	return model[im.Cube().String()]
+}
+
+func TestEspressoCheaper(t *testing.T) {
+	cover := func(cubes ...string) []implicant {
+		implicants := []implicant{}
+		for _, s := range cubes {
+			c, err := ParseCube(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			implicants = append(implicants, c.im)
+		}
+		return implicants
+	}
+
+	// 0.1 + 0.2 differs from 0.15 + 0.15 by rounding, so the covers cost the
+	// same and the cover of fewer literals is cheaper
+	e := espresso{model: stringCost{"1x": 0.1, "x1": 0.2, "11": 0.15, "00": 0.15}, width: 2}
+	fewer, more := cover("1x", "x1"), cover("11", "00")
+	if !e.cheaper(fewer, more) || e.cheaper(more, fewer) {
+		t.Errorf("cheaper() does not prefer the cover of fewer literals when the costs differ by rounding")
+	}
+}
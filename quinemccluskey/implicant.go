@@ -1,17 +1,13 @@
 package quinemccluskey
 
-import (
-	"strconv"
-)
-
 // implicant represents a cover of one or more minterms.
 type implicant struct {
 	// literals is the input bits that are strictly '0' or '1'.
-	literals uint64
+	literals bitset
 	// xMask is the mask of input bits that may be either '0' or '1'.
-	xMask uint64
+	xMask bitset
 	// tag decribes the outputs for which the implicant applies.
-	tag uint64
+	tag bitset
 	// checked is set to true when the implicant is successfully combined
 	// with another implicant, and the resulting implicant's tag is equal
 	// to the input implicant. Remaining unchecked implicants are prime.
@@ -29,22 +25,24 @@ func (im1 *implicant) combine(im2 *implicant) implicant {
 	}
 
 	// implicants may be combined if they differ by exactly one bit
-	literalsDelta := im1.literals ^ im2.literals
-	if bitCount(literalsDelta) != 1 {
+	literalsDelta := im1.literals.xor(im2.literals)
+	if literalsDelta.count() != 1 {
 		return implicant{}
 	}
 
 	// build combination implicant
 	im := implicant{
-		literals: im1.literals &^ literalsDelta, // set differing bit to '0' in literal
-		xMask:    im1.xMask | literalsDelta,     // add differing bit to xMask
-		tag:      im1.tag & im2.tag,             // tag becomes the logical product of previous tags
-		checked:  false,                         // checked is false for new implicants
+		literals: im1.literals.andNot(literalsDelta), // set differing bit to '0' in literal
+		xMask:    im1.xMask.or(literalsDelta),        // add differing bit to xMask
+		tag:      im1.tag.and(im2.tag),               // tag becomes the logical product of previous tags
+		checked:  false,                              // checked is false for new implicants
 	}
 
-	// mark input implicants as checked
-	im1.checked = (im1.tag == im.tag)
-	im2.checked = (im2.tag == im.tag)
+	// mark input implicants as checked. An implicant remains checked once it
+	// has been combined without losing an output, whatever the order in
+	// which it is combined with others
+	im1.checked = im1.checked || (im1.tag == im.tag)
+	im2.checked = im2.checked || (im2.tag == im.tag)
 
 	return im
 }
@@ -54,9 +52,69 @@ func (im1 implicant) equals(im2 implicant) bool {
 	return (im1.literals == im2.literals) && (im1.xMask == im2.xMask)
 }
 
+// less defines the canonical order of implicants, used wherever implicants are
+// listed so that results do not depend on the order in which they were
+// generated. Implicants are ordered by their xMask, then their literals, and
+// then their tag, each compared as an unsigned integer.
+func (im1 implicant) less(im2 implicant) bool {
+	if im1.xMask != im2.xMask {
+		return im1.xMask.less(im2.xMask)
+	}
+	if im1.literals != im2.literals {
+		return im1.literals.less(im2.literals)
+	}
+	return im1.tag.less(im2.tag)
+}
+
 // covers tests whether the calling implicant covers the passed minterm
-func (im implicant) covers(minterm uint64) bool {
-	return ((im.xMask & minterm) | im.literals) == minterm
+func (im implicant) covers(minterm bitset) bool {
+	return im.xMask.and(minterm).or(im.literals) == minterm
+}
+
+// intersects tests whether the input parts of the calling and passed
+// implicants share at least one minterm.
+func (im1 implicant) intersects(im2 implicant) bool {
+	return im1.literals.xor(im2.literals).andNot(im1.xMask.or(im2.xMask)).isZero()
+}
+
+// contains tests whether every minterm covered by the input part of the passed
+// implicant is also covered by the calling implicant.
+func (im1 implicant) contains(im2 implicant) bool {
+	return im2.xMask.andNot(im1.xMask).isZero() && im1.literals.xor(im2.literals).andNot(im1.xMask).isZero()
+}
+
+// intersection returns the implicant covering the minterms covered by both the
+// calling and passed implicants, for the outputs they have in common. The
+// implicants are assumed to intersect.
+func (im1 implicant) intersection(im2 implicant) implicant {
+	return implicant{
+		literals: im1.literals.or(im2.literals),
+		xMask:    im1.xMask.and(im2.xMask),
+		tag:      im1.tag.and(im2.tag),
+	}
+}
+
+// supercube returns the smallest implicant covering the minterms of both the
+// calling and passed implicants, for the outputs of either.
+func (im1 implicant) supercube(im2 implicant) implicant {
+	xMask := im1.xMask.or(im2.xMask).or(im1.literals.xor(im2.literals))
+	return implicant{
+		literals: im1.literals.and(im2.literals).andNot(xMask),
+		xMask:    xMask,
+		tag:      im1.tag.or(im2.tag),
+	}
+}
+
+// cofactor returns the cofactor of the calling implicant with respect to the
+// passed implicant, in which every input that is a literal in p becomes an
+// 'x'. The implicants are assumed to intersect.
+func (im implicant) cofactor(p implicant, full bitset) implicant {
+	fixed := full.andNot(p.xMask)
+	return implicant{
+		literals: im.literals.andNot(fixed),
+		xMask:    im.xMask.or(fixed),
+		tag:      im.tag,
+	}
 }
 
 // outputList returns a list of all outputs the calling implicant applies to in
@@ -64,8 +122,8 @@ func (im implicant) covers(minterm uint64) bool {
 func (im implicant) outputList() []int {
 	outputs := []int{}
 
-	for i := 0; i <= msbPos(im.tag); i++ {
-		if (im.tag>>i)&1 == 1 {
+	for i := 0; i < im.tag.msbPos(); i++ {
+		if im.tag.bit(i) {
 			outputs = append(outputs, i)
 		}
 	}
@@ -76,15 +134,50 @@ func (im implicant) outputList() []int {
 // stringify returns a string representation of the calling implicant using
 // '1's, '0's, and 'x's as determined from its `term` and `xMask` members
 func (im implicant) stringify(bits int) string {
-	s := ""
-	for i := 0; i < bits; i++ {
-		if ((im.xMask >> (bits - 1 - i)) & 1) == 1 {
-			s += "x"
-			continue
+	s := []byte(im.literals.binary(bits))
+	for i := range s {
+		if im.xMask.bit(bits - 1 - i) {
+			s[i] = 'x'
 		}
-
-		s += strconv.FormatUint((im.literals>>(bits-1-i))&1, 10)
 	}
 
-	return s
+	return string(s)
+}
+
+// Implicant is a product of a LogicFunction's inputs, applying to one or more
+// of its outputs.
+type Implicant struct {
+	im    implicant
+	width int
+}
+
+// String returns a representation of the implicant using '1's, '0's, and 'x's
+// for each input, with the most significant input first.
+func (im Implicant) String() string {
+	return im.im.stringify(im.width)
+}
+
+// Outputs returns a list of all outputs the implicant applies to.
+func (im Implicant) Outputs() []int {
+	return im.im.outputList()
+}
+
+// LiteralCount returns the number of inputs which appear in the implicant as
+// a literal.
+func (im Implicant) LiteralCount() int {
+	return im.width - im.im.xMask.count()
+}
+
+// Literals returns the value of each input in the implicant as a '1' or '0',
+// with the most significant input first. Inputs which do not appear as a
+// literal are '0'.
+func (im Implicant) Literals() string {
+	return im.im.literals.binary(im.width)
+}
+
+// DontCareMask returns a '1' for each input which does not appear in the
+// implicant as a literal, and a '0' for each input which does, with the most
+// significant input first.
+func (im Implicant) DontCareMask() string {
+	return im.im.xMask.binary(im.width)
 }
@@ -1,14 +1,14 @@
 package quinemccluskey
 
 import (
-	"fmt"
+	"context"
 	"sync"
 )
 
 // implicantColumn is a list of implicants divided into groups.
 type implicantColumn []map[implicant]bool
 
-func processGroup(group int, column *implicantColumn, newColumn *implicantColumn) {
+func processGroup(ctx context.Context, group int, column *implicantColumn, newColumn *implicantColumn) {
 	list0 := []implicant{}
 	for im := range (*column)[group] {
 		list0 = append(list0, im)
@@ -25,13 +25,16 @@ func processGroup(group int, column *implicantColumn, newColumn *implicantColumn
 
 	// try to combine implicants and add the result to the new group
 	for i := range list0 {
+		if ctx.Err() != nil {
+			return
+		}
 		for j := range list1 {
-			if list0[i].tag&list1[j].tag != 0 {
+			if !list0[i].tag.and(list1[j].tag).isZero() {
 				delete((*column)[group], list0[i])
 				delete((*column)[group+1], list1[j])
 
 				newImplicant := list0[i].combine(&list1[j])
-				if newImplicant.tag != 0 {
+				if !newImplicant.tag.isZero() {
 					(*newColumn)[group][newImplicant] = true
 				}
 
@@ -40,14 +43,20 @@ func processGroup(group int, column *implicantColumn, newColumn *implicantColumn
 			}
 		}
 	}
-	fmt.Printf("%d/%d\n", group, len(*column)-1)
 }
 
 // iterate attempts to combine implicants with those in consecutive groups.
 // Sucessful combinations are added to a new implicantColumn with a group for
 // each pair of consecutive groups in the previous implicantColumn. The
-// resulting new implicantColumn is returned.
-func (column *implicantColumn) iterate() implicantColumn {
+// resulting new implicantColumn is returned. If the context is done, the
+// combination is abandoned and the new implicantColumn is incomplete.
+func (column *implicantColumn) iterate(ctx context.Context) implicantColumn {
+	// a column of fewer than two groups, such as that of a function with no
+	// terms, has no implicants to combine
+	if len(*column) < 2 {
+		return implicantColumn{}
+	}
+
 	var newColumn implicantColumn = make([]map[implicant]bool, len(*column)-1)
 
 	var wg sync.WaitGroup
@@ -61,7 +70,7 @@ func (column *implicantColumn) iterate() implicantColumn {
 		group := group
 		go func() {
 			defer wg.Done()
-			processGroup(group, column, &newColumn)
+			processGroup(ctx, group, column, &newColumn)
 		}()
 	}
 
@@ -76,7 +85,7 @@ func (column *implicantColumn) iterate() implicantColumn {
 		group := group
 		go func() {
 			defer wg.Done()
-			processGroup(group, column, &newColumn)
+			processGroup(ctx, group, column, &newColumn)
 		}()
 	}
 
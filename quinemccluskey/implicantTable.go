@@ -1,7 +1,9 @@
 package quinemccluskey
 
 import (
-	"strconv"
+	"context"
+
+	"golang.org/x/exp/slices"
 )
 
 // implicant table is list of implicantColumns representing iterations of
@@ -11,36 +13,31 @@ type implicantTable struct {
 	columns  []implicantColumn
 }
 
-// init sets nOutputs, and initializes a column with enough space in each group
-// for the max number of possible set bits to be added.
+// init sets nOutputs, and initializes an empty first column. Groups are added
+// to the column as terms with more set bits are added.
 func (table *implicantTable) init() {
 	table.nOutputs = 0
-	table.columns = []implicantColumn{make(implicantColumn, 0, 64)}
+	table.columns = []implicantColumn{{}}
 }
 
 // addOutput takes a list of minterms and don't cares, and sorts them into
 // groups for the calling implicantTable corresponding to the number of set
 // bits in the term. For each output added, the corresponding tag bit is set
 // to '1', and nOutputs is incremented.
-func (table *implicantTable) addOutput(minterms []uint64, dontCares []uint64, printoutsEnabled bool) int {
-	// maximum number of outputs exceeded
-	if table.nOutputs == 64 {
-		return -1
-	}
-
+func (table *implicantTable) addOutput(minterms []bitset, dontCares []bitset) {
 	// copy all terms into a single new list
-	terms := []uint64{}
+	terms := []bitset{}
 	terms = append(terms, minterms...)
 	terms = append(terms, dontCares...)
 
 	// add minterms to groups based on the number of set bits
 NEXT_TERM:
 	for _, term := range terms {
-		setBits := bitCount(term)
+		setBits := term.count()
 
 		// grow the slice to accomodate setBits as index
-		if setBits >= len(table.columns[0]) {
-			table.columns[0] = table.columns[0][:setBits+1]
+		for setBits >= len(table.columns[0]) {
+			table.columns[0] = append(table.columns[0], nil)
 		}
 
 		// if the implicant already exists, set its tag bit for this output
@@ -48,7 +45,7 @@ NEXT_TERM:
 			if im.literals == term {
 				newImplicant := im
 				delete(table.columns[0][setBits], im)
-				newImplicant.tag |= 1 << table.nOutputs
+				newImplicant.tag = newImplicant.tag.with(table.nOutputs)
 				table.columns[0][setBits][newImplicant] = true
 				continue NEXT_TERM
 			}
@@ -59,30 +56,27 @@ NEXT_TERM:
 		}
 
 		// otherwise build and add an implicant with its tag bit set for this output
-		im := implicant{term, 0, (1 << table.nOutputs), false}
+		im := implicant{term, bitset{}, singleBit(table.nOutputs), false}
 		table.columns[0][setBits][im] = true
 	}
 
 	table.nOutputs++
-
-	visualizeLogicFunction(minterms, dontCares, printoutsEnabled)
-
-	return 0
 }
 
 // reduce will solve the table by iterating columns until no new combinations
 // can be made. After this process, the terms in each column that are still
-// unchecked are prime implicants, which are placed in a list and returned.
-func (table *implicantTable) reduce(implicantDisplayWidth int, mintermDisplayWidth int, printoutsEnabled bool) []implicant {
+// unchecked are prime implicants, which are placed in a list in canonical
+// order and returned.
+// Each column and the resulting primes are passed to the observer. If the
+// context is done, iteration stops and the returned list is incomplete.
+func (table *implicantTable) reduce(ctx context.Context, implicantDisplayWidth int, observer Observer) []implicant {
 	// iterate lists until no more combinations can be made
-	visualizeHeading("TABLE: 0", printoutsEnabled)
-	table.visualize(implicantDisplayWidth, printoutsEnabled)
-	nextColumn := table.columns[len(table.columns)-1].iterate()
-	for iter := 1; len(nextColumn) > 0; iter++ {
+	table.notifyColumn(0, implicantDisplayWidth, observer)
+	nextColumn := table.columns[len(table.columns)-1].iterate(ctx)
+	for iter := 1; len(nextColumn) > 0 && ctx.Err() == nil; iter++ {
 		table.columns = append(table.columns, nextColumn)
-		visualizeHeading("TABLE: "+strconv.FormatInt(int64(iter), 10), printoutsEnabled)
-		table.visualize(implicantDisplayWidth, printoutsEnabled)
-		nextColumn = table.columns[len(table.columns)-1].iterate()
+		table.notifyColumn(iter, implicantDisplayWidth, observer)
+		nextColumn = table.columns[len(table.columns)-1].iterate(ctx)
 	}
 
 	// add unchecked implicants from all lists to a new list
@@ -91,8 +85,46 @@ func (table *implicantTable) reduce(implicantDisplayWidth int, mintermDisplayWid
 	for _, list := range table.columns {
 		primes = append(primes, list.primes()...)
 	}
+	slices.SortFunc(primes, implicant.less)
 
-	visualizePrimeImplicantList(primes, implicantDisplayWidth, printoutsEnabled)
+	notify(observer, PrimesFoundEvent{exportImplicants(primes, implicantDisplayWidth)})
 
 	return primes
 }
+
+// notifyColumn passes a ColumnGeneratedEvent for the passed column to the
+// observer, with a snapshot of every column of the calling implicantTable.
+func (table *implicantTable) notifyColumn(column int, implicantDisplayWidth int, observer Observer) {
+	if observer == nil {
+		return
+	}
+
+	// progress only needs the size of the column, not a snapshot of the table
+	if p, ok := progressOnly(observer); ok {
+		implicants := 0
+		for _, group := range table.columns[column] {
+			implicants += len(group)
+		}
+		p.columnGenerated(column, implicants)
+		return
+	}
+
+	columns := [][][]TableEntry{}
+	for k, c := range table.columns {
+		columns = append(columns, [][]TableEntry{})
+		for _, group := range c {
+			entries := []implicant{}
+			for entry := range group {
+				entries = append(entries, entry)
+			}
+			slices.SortFunc(entries, implicant.less)
+
+			columns[k] = append(columns[k], []TableEntry{})
+			for _, entry := range entries {
+				columns[k][len(columns[k])-1] = append(columns[k][len(columns[k])-1], TableEntry{Implicant{entry, implicantDisplayWidth}, entry.checked})
+			}
+		}
+	}
+
+	observer.Observe(ColumnGeneratedEvent{column, columns, table.nOutputs})
+}
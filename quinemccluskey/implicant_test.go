@@ -0,0 +1,65 @@
+package quinemccluskey
+
+import (
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+// primesObserver records the primes found by the QuineMcCluskey engine.
+type primesObserver struct {
+	primes []Implicant
+}
+
+func (o *primesObserver) Observe(event Event) {
+	if e, ok := event.(PrimesFoundEvent); ok {
+		o.primes = append(o.primes, e.Primes...)
+	}
+}
+
+// TestPrimesAreMaximal checks that no prime found by the implicant table is
+// contained by another prime for all of its outputs, which would happen if an
+// implicant combined without losing an output were unchecked by a later
+// combination which loses one.
+func TestPrimesAreMaximal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		inputs, outputs := 2+rng.Intn(3), 2+rng.Intn(2)
+
+		var f LogicFunction
+		f.Init(false)
+		if err := f.SetNumInputs(inputs); err != nil {
+			t.Fatal(err)
+		}
+		for o := 0; o < outputs; o++ {
+			minterms := []uint64{}
+			for term := uint64(0); term < 1<<inputs; term++ {
+				if rng.Intn(2) == 1 {
+					minterms = append(minterms, term)
+				}
+			}
+			if err := f.AddOutput(minterms, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		observer := &primesObserver{}
+		f.SetObserver(observer)
+		if r := f.GetMinimumCostCover(InputLabels{}, OutputLabels{}); r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		for _, p := range observer.primes {
+			for _, q := range observer.primes {
+				if p.String() == q.String() || !q.Cube().Contains(p.Cube()) {
+					continue
+				}
+				if slices.IndexFunc(p.Outputs(), func(o int) bool { return !slices.Contains(q.Outputs(), o) }) < 0 {
+					t.Errorf("%d: prime %s of outputs %v is contained by %s of outputs %v", i, p, p.Outputs(), q, q.Outputs())
+				}
+			}
+		}
+	}
+}
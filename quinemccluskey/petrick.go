@@ -0,0 +1,158 @@
+package quinemccluskey
+
+import (
+	"math"
+	"sort"
+)
+
+// appendAbsorbed adds the product p to a list of products in which no product
+// is a superset of another. If p is absorbed by an existing product the list
+// is returned unchanged, otherwise any products absorbed by p are removed.
+func appendAbsorbed(products []indexSet, p indexSet) []indexSet {
+	for _, q := range products {
+		if q.subsetOf(p) {
+			return products
+		}
+	}
+
+	kept := products[:0]
+	for _, q := range products {
+		if !p.subsetOf(q) {
+			kept = append(kept, q)
+		}
+	}
+
+	return append(kept, p)
+}
+
+// petrickSums returns a sum of primes for every row of the passed
+// CoverProblem, each listing the primes which cover that row. Sums which are
+// absorbed by another sum are dropped, and the rest are ordered from fewest to
+// most primes to keep the intermediate expansion small.
+func petrickSums(problem CoverProblem) []indexSet {
+	sums := make([]indexSet, len(problem.Rows))
+	for r := range sums {
+		sums[r] = newIndexSet(len(problem.Primes))
+	}
+
+	for p, cover := range problem.Covers {
+		for _, r := range cover {
+			sums[r].add(p)
+		}
+	}
+
+	// (x + y)(x + y + z) = (x + y)
+	absorbed := []indexSet{}
+	for _, sum := range sums {
+		absorbed = appendAbsorbed(absorbed, sum)
+	}
+
+	sort.SliceStable(absorbed, func(i, j int) bool {
+		return absorbed[i].count() < absorbed[j].count()
+	})
+
+	return absorbed
+}
+
+// multiplySum multiplies a sum of products by a sum of primes, returning the
+// expanded sum of products with absorption applied. If the problem is
+// cancelled, the products are returned unexpanded.
+func multiplySum(problem CoverProblem, products []indexSet, sum indexSet) []indexSet {
+	expanded := []indexSet{}
+
+	for _, product := range products {
+		if problem.cancelled() {
+			return products
+		}
+
+		// x(x + y) = x
+		if product.intersects(sum) {
+			expanded = appendAbsorbed(expanded, product)
+			continue
+		}
+
+		for _, p := range sum.indices() {
+			expanded = appendAbsorbed(expanded, product.with(p))
+		}
+	}
+
+	return expanded
+}
+
+// petrickProducts returns every irredundant cover of the rows of the passed
+// CoverProblem, found by expanding the product of the sums returned by
+// petrickSums into a sum of products. If the problem is cancelled, the
+// partially expanded products are returned.
+func petrickProducts(problem CoverProblem) []indexSet {
+	products := []indexSet{newIndexSet(len(problem.Primes))}
+	for _, sum := range petrickSums(problem) {
+		if problem.cancelled() {
+			break
+		}
+		products = multiplySum(problem, products, sum)
+	}
+	return products
+}
+
+// PetrickSolver solves a CoverProblem exactly using Petrick's method. The
+// result is a minimum cost cover, but runtime and memory can grow
+// exponentially with the size of the problem.
+type PetrickSolver struct{}
+
+// SolveCover implements CoverSolver. The cheapest of the products returned by
+// petrickProducts is returned.
+func (PetrickSolver) SolveCover(problem CoverProblem) ([]int, bool) {
+	products := petrickProducts(problem)
+
+	// a row covered by no prime leaves no products
+	if len(products) == 0 {
+		return []int{}, false
+	}
+
+	best := products[0]
+	bestCost := productCost(problem, best)
+	for _, product := range products[1:] {
+		cost := productCost(problem, product)
+		if cost < bestCost {
+			best = product
+			bestCost = cost
+		}
+	}
+
+	return best.indices(), !problem.cancelled()
+}
+
+// EnumerateCovers implements CoverEnumerator. Every product returned by
+// petrickProducts with the lowest cost is returned.
+func (PetrickSolver) EnumerateCovers(problem CoverProblem, limit int) ([][]int, bool) {
+	products := petrickProducts(problem)
+	if len(products) == 0 {
+		return [][]int{}, false
+	}
+
+	bestCost := productCost(problem, products[0])
+	for _, product := range products[1:] {
+		bestCost = math.Min(bestCost, productCost(problem, product))
+	}
+
+	covers := [][]int{}
+	for _, product := range products {
+		if limit > 0 && len(covers) == limit {
+			break
+		}
+		if costsEqual(productCost(problem, product), bestCost) {
+			covers = append(covers, product.indices())
+		}
+	}
+
+	return covers, !problem.cancelled()
+}
+
+// productCost returns the total cost of the primes in the passed product.
+func productCost(problem CoverProblem, product indexSet) float64 {
+	cost := 0.0
+	for _, p := range product.indices() {
+		cost += problem.Cost(p)
+	}
+	return cost
+}
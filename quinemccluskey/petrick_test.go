@@ -0,0 +1,100 @@
+package quinemccluskey
+
+import (
+	"context"
+	"testing"
+)
+
+// newTestProblem returns a CoverProblem of the passed number of rows, with a
+// prime of each of the passed costs covering the passed rows.
+func newTestProblem(rows int, costs []float64, covers [][]int) CoverProblem {
+	problem := CoverProblem{
+		Primes: make([]Implicant, len(costs)),
+		Rows:   make([]CoverRow, rows),
+		Covers: covers,
+		Cost:   func(p int) float64 { return costs[p] },
+	}
+	for r := range problem.Rows {
+		problem.Rows[r] = CoverRow{0, bitset{word: uint64(r)}.binary(8)}
+	}
+	return problem
+}
+
+// selectionCost returns the cost of the selected primes of a CoverProblem,
+// and fails the test if they do not cover every row.
+func selectionCost(t *testing.T, problem CoverProblem, selected []int) float64 {
+	t.Helper()
+
+	covered := map[int]bool{}
+	cost := 0.0
+	for _, p := range selected {
+		for _, r := range problem.Covers[p] {
+			covered[r] = true
+		}
+		cost += problem.Cost(p)
+	}
+	if len(covered) != len(problem.Rows) {
+		t.Errorf("selection %v covers %d of %d rows", selected, len(covered), len(problem.Rows))
+	}
+
+	return cost
+}
+
+// coverProblemTests holds cover problems with their minimum cost, and the
+// number of selections of that cost.
+var coverProblemTests = []struct {
+	name   string
+	rows   int
+	costs  []float64
+	covers [][]int
+	cost   float64
+	count  int
+}{
+	{"single row", 1, []float64{2, 1}, [][]int{{0}, {0}}, 1, 1},
+	{"essential primes", 3, []float64{1, 1, 1}, [][]int{{0}, {1, 2}, {2}}, 2, 1},
+	{"cheaper primes", 3, []float64{3, 0.5, 0.5, 0.5}, [][]int{{0, 1, 2}, {0}, {1}, {2}}, 1.5, 1},
+	{"cyclic", 6, []float64{1, 1, 1, 1, 1, 1}, [][]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 0}}, 3, 2},
+	{"cyclic with cost", 6, []float64{1, 2, 1, 2, 1, 2}, [][]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 0}}, 3, 1},
+	{"equal covers", 4, []float64{2, 2, 1, 1, 1, 1}, [][]int{{0, 1}, {2, 3}, {0}, {1}, {2}, {3}}, 4, 4},
+}
+
+func TestPetrickSolver(t *testing.T) {
+	for _, test := range coverProblemTests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := newTestProblem(test.rows, test.costs, test.covers)
+
+			selected, optimal := PetrickSolver{}.SolveCover(problem)
+			if cost := selectionCost(t, problem, selected); !costsEqual(cost, test.cost) || !optimal {
+				t.Errorf("SolveCover() = %v with cost %v, optimal %v, want cost %v, optimal", selected, cost, optimal, test.cost)
+			}
+
+			covers, optimal := PetrickSolver{}.EnumerateCovers(problem, 0)
+			if len(covers) != test.count || !optimal {
+				t.Errorf("EnumerateCovers() = %v, optimal %v, want %d covers, optimal", covers, optimal, test.count)
+			}
+			for _, cover := range covers {
+				if cost := selectionCost(t, problem, cover); !costsEqual(cost, test.cost) {
+					t.Errorf("EnumerateCovers() cover %v has cost %v, want %v", cover, cost, test.cost)
+				}
+			}
+
+			if covers, _ := (PetrickSolver{}).EnumerateCovers(problem, 1); len(covers) != 1 {
+				t.Errorf("EnumerateCovers() with limit 1 = %v, want 1 cover", covers)
+			}
+		})
+	}
+}
+
+func TestPetrickCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	problem := newTestProblem(2, []float64{1, 1}, [][]int{{0}, {1}})
+	problem.Context = ctx
+
+	// a cancelled expansion returns the products unexpanded
+	products := []indexSet{newIndexSet(2)}
+	sum := newIndexSet(2).with(0).with(1)
+	if expanded := multiplySum(problem, products, sum); len(expanded) != 1 || expanded[0].count() != 0 {
+		t.Errorf("multiplySum() of a cancelled problem = %v, want the products unexpanded", expanded)
+	}
+}
@@ -0,0 +1,156 @@
+package quinemccluskey
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// busBit matches the label of a bit of a bus, such as "addr[3]", capturing the
+// name of the bus and the index of the bit.
+var busBit = regexp.MustCompile(`^(.+)\[(\d+)\]$`)
+
+// port is a port of a generated module, which is either a single signal, or a
+// bus holding the signals with the labels of its bits.
+type port struct {
+	// label is the label of the signal, or the name of the bus.
+	label string
+	// bus reports whether the port is a bus of the bits from msb down to lsb.
+	bus      bool
+	msb, lsb int
+	// bits holds the index in the bus of each signal of the port.
+	bits map[int]int
+}
+
+// groupPorts returns the ports holding the signals with the passed labels, in
+// the order in which their first signal is labelled. If buses is set, signals
+// labelled as the bits of a bus with a contiguous range of bits are grouped
+// into a single port, and otherwise each signal is a port of its own.
+func groupPorts(labels []string, buses bool) []port {
+	ports := []port{}
+	busPorts := map[string]int{}
+
+	for signal, label := range labels {
+		if match := busBit.FindStringSubmatch(label); buses && match != nil {
+			if bit, err := strconv.Atoi(match[2]); err == nil {
+				if p, ok := busPorts[match[1]]; ok {
+					ports[p].bits[signal] = bit
+					continue
+				}
+				busPorts[match[1]] = len(ports)
+				ports = append(ports, port{label: match[1], bus: true, bits: map[int]int{signal: bit}})
+				continue
+			}
+		}
+
+		ports = append(ports, port{label: label, bits: map[int]int{signal: 0}})
+	}
+
+	// a bus whose bits are repeated or have gaps is split into its signals
+	grouped := []port{}
+	for _, p := range ports {
+		if !p.bus {
+			grouped = append(grouped, p)
+			continue
+		}
+
+		p.msb, p.lsb = -1, -1
+		seen := map[int]bool{}
+		for _, bit := range p.bits {
+			seen[bit] = true
+			if p.msb < 0 || bit > p.msb {
+				p.msb = bit
+			}
+			if p.lsb < 0 || bit < p.lsb {
+				p.lsb = bit
+			}
+		}
+		if len(seen) == len(p.bits) && p.msb-p.lsb+1 == len(p.bits) {
+			grouped = append(grouped, p)
+			continue
+		}
+
+		for signal := range labels {
+			if _, ok := p.bits[signal]; ok {
+				grouped = append(grouped, port{label: labels[signal], bits: map[int]int{signal: 0}})
+			}
+		}
+	}
+
+	return grouped
+}
+
+// uniqueIdentifier returns name, or name followed by '_' and a number if name
+// is already used, and marks the returned identifier as used. Identifiers are
+// compared by the passed key, which allows for case insensitive languages.
+func uniqueIdentifier(name string, used map[string]bool, key func(string) string) string {
+	identifier := name
+	for n := 2; used[key(identifier)]; n++ {
+		identifier = fmt.Sprintf("%s_%d", name, n)
+	}
+	used[key(identifier)] = true
+	return identifier
+}
+
+// sharedTerm identifies a term of a Result's equations, which is either a
+// product of the implicant's literals or, for an output in the ProductOfSums
+// form, a sum of its complemented literals.
+type sharedTerm struct {
+	form Form
+	cube string
+}
+
+// portLabels returns the labels of the inputs of a Result, with the most
+// significant first, and of its outputs.
+func portLabels(r Result) ([]string, []string) {
+	inLabels := []string{}
+	for i := r.NumInputs - 1; i >= 0; i-- {
+		inLabels = append(inLabels, r.inLabels.Str(i))
+	}
+
+	outLabels := []string{}
+	for o := range r.Outputs {
+		outLabels = append(outLabels, r.outLabels.Str(o))
+	}
+
+	return inLabels, outLabels
+}
+
+// sharedTerms returns the terms of a Result's equations of more than one
+// literal which appear in the equations of more than one output, in the order
+// they first appear, and an implicant giving each term.
+func sharedTerms(r Result) ([]sharedTerm, map[sharedTerm]Implicant) {
+	terms := []sharedTerm{}
+	implicants := map[sharedTerm]Implicant{}
+	outputs := map[sharedTerm]int{}
+
+	for _, output := range r.Outputs {
+		for _, im := range output.Implicants {
+			if im.LiteralCount() < 2 {
+				continue
+			}
+			term := sharedTerm{output.Form, im.String()}
+			outputs[term]++
+			if outputs[term] == 2 {
+				terms = append(terms, term)
+				implicants[term] = im
+			}
+		}
+	}
+
+	return terms, implicants
+}
+
+// sharedNames returns a unique identifier for each of the passed shared terms,
+// where products are named p0, p1, ..., and sums s0, s1, ...
+func sharedNames(terms []sharedTerm, used map[string]bool, key func(string) string) map[sharedTerm]string {
+	names := map[sharedTerm]string{}
+	for _, term := range terms {
+		prefix := "p"
+		if term.form == ProductOfSums {
+			prefix = "s"
+		}
+		names[term] = uniqueIdentifier(fmt.Sprintf("%s%d", prefix, len(names)), used, key)
+	}
+	return names
+}
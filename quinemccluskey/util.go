@@ -24,17 +24,6 @@ func msbPos(v uint64) int {
 	return b
 }
 
-// bitLimit returns the max integer representable with the same number of bits
-// required to represent v.
-func bitLimit(v uint64) uint64 {
-	v |= v >> 2
-	v |= v >> 4
-	v |= v >> 8
-	v |= v >> 16
-	v |= v >> 32
-	return v
-}
-
 // insert inserts v into a sorted set s.
 func insert[T comparable](s []T, v T, f func(i int) bool) []T {
 	if len(s) == 0 {
@@ -52,3 +41,101 @@ func insert[T comparable](s []T, v T, f func(i int) bool) []T {
 
 	return s
 }
+
+// indexSet is a set of small non-negative integers, such as indices into the
+// primes or remaining minterms of a coverTable.
+type indexSet []uint64
+
+// newIndexSet returns an empty indexSet able to hold the indices 0 to n-1.
+func newIndexSet(n int) indexSet {
+	return make(indexSet, (n+63)/64)
+}
+
+// has tests whether the index i is a member of the calling indexSet.
+func (s indexSet) has(i int) bool {
+	return (s[i/64]>>(i%64))&1 == 1
+}
+
+// with returns a copy of the calling indexSet with the index i added.
+func (s indexSet) with(i int) indexSet {
+	t := s.clone()
+	t.add(i)
+	return t
+}
+
+// add inserts the index i into the calling indexSet.
+func (s indexSet) add(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+// remove deletes the index i from the calling indexSet.
+func (s indexSet) remove(i int) {
+	s[i/64] &^= 1 << (i % 64)
+}
+
+// clone returns a copy of the calling indexSet.
+func (s indexSet) clone() indexSet {
+	t := make(indexSet, len(s))
+	copy(t, s)
+	return t
+}
+
+// intersection returns a new indexSet holding the indices in both the calling
+// indexSet and t.
+func (s indexSet) intersection(t indexSet) indexSet {
+	u := make(indexSet, len(s))
+	for i := range s {
+		u[i] = s[i] & t[i]
+	}
+	return u
+}
+
+// union returns a new indexSet holding the indices in either the calling
+// indexSet or t.
+func (s indexSet) union(t indexSet) indexSet {
+	u := make(indexSet, len(s))
+	for i := range s {
+		u[i] = s[i] | t[i]
+	}
+	return u
+}
+
+// intersects tests whether the calling indexSet shares any index with t.
+func (s indexSet) intersects(t indexSet) bool {
+	for i := range s {
+		if s[i]&t[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// subsetOf tests whether every index in the calling indexSet is also in t.
+func (s indexSet) subsetOf(t indexSet) bool {
+	for i := range s {
+		if s[i]&^t[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// count returns the number of indices in the calling indexSet.
+func (s indexSet) count() int {
+	n := 0
+	for _, word := range s {
+		n += bitCount(word)
+	}
+	return n
+}
+
+// indices returns the members of the calling indexSet in ascending order.
+func (s indexSet) indices() []int {
+	list := []int{}
+	for i := 0; i < len(s)*64; i++ {
+		if s.has(i) {
+			list = append(list, i)
+		}
+	}
+	return list
+}
@@ -2,196 +2,254 @@ package quinemccluskey
 
 import (
 	"fmt"
+	"io"
 	"math"
-	"sort"
 	"strconv"
 	"strings"
 
 	"golang.org/x/exp/slices"
 )
 
-// visualizeHeading conditionally prints a line of text stdout
-func visualizeHeading(text string, show bool) {
-	if show {
-		fmt.Println(text)
+// TextObserver is an Observer which writes each step taken to minimize a
+// LogicFunction to Writer as a heading followed by an ASCII table or list.
+type TextObserver struct {
+	Writer io.Writer
+}
+
+// Observe writes a representation of the passed event to the TextObserver's
+// Writer.
+func (t TextObserver) Observe(event Event) {
+	switch e := event.(type) {
+	case OutputAddedEvent:
+		t.visualizeLogicFunction(e.Minterms, e.DontCares, e.OffSet)
+	case MinimizeStartedEvent:
+		if e.Form == ProductOfSums {
+			t.visualizeHeading("PRODUCT OF SUMS: MINIMIZING MAXTERMS")
+		}
+	case ColumnGeneratedEvent:
+		t.visualizeHeading("TABLE: " + strconv.Itoa(e.Column))
+		t.visualizeImplicantTable(e.Columns, e.NumOutputs)
+	case PrimesFoundEvent:
+		t.visualizePrimeImplicantList(e.Primes)
+	case CoverTableBuiltEvent:
+		t.visualizeCoverTable(e.Table)
+	case EssentialPrimesRemovedEvent:
+		t.visualizeHeading("ESSENTIAL PRIMES REMOVED")
+		t.visualizeCoverTable(e.Table)
+	case ImplicantSelectedEvent:
+		t.visualizeHeading(e.Implicant.String() + " REMOVED")
+		t.visualizeCoverTable(e.Table)
+	case MinimumCoverFoundEvent:
+		t.visualizeCover("MINIMUM COST COVER "+strconv.Itoa(e.Index+1), e.Cover, e.NumOutputs)
+	case EspressoStepEvent:
+		heading := "ESPRESSO: INITIAL COVER"
+		switch e.Step {
+		case EspressoReduceExpandIrredundant:
+			heading = "ESPRESSO: REDUCE, EXPAND, IRREDUNDANT"
+		case EspressoLastGasp:
+			heading = "ESPRESSO: LAST GASP"
+		}
+		t.visualizeCover(heading, e.Cover, e.NumOutputs)
 	}
 }
 
+// visualizeHeading prints a line of text.
+func (t TextObserver) visualizeHeading(text string) {
+	fmt.Fprintln(t.Writer, text)
+}
+
+// decimalTerm returns the decimal representation of a term given as a string
+// of '1's and '0's.
+func decimalTerm(term string) string {
+	b, _ := parseBinary(term)
+	return b.decimal()
+}
+
 // ----------------------------------------------------------------
 //   IMPLICANT TABLE VISUALIZATION
 // ----------------------------------------------------------------
 
 // visualizeLogicFunctionList prints a comma seperated list wrapped with
-// parentheses to stdout for use by visualizeLogicFunction.
-func visualizeLogicFunctionList(list []uint64) {
-	fmt.Printf("(")
+// parentheses for use by visualizeLogicFunction.
+func (t TextObserver) visualizeLogicFunctionList(list []string) {
+	fmt.Fprintf(t.Writer, "(")
 	for i, item := range list {
-		fmt.Printf("%d", item)
+		fmt.Fprintf(t.Writer, "%s", decimalTerm(item))
 		if i != len(list)-1 {
-			fmt.Printf(", ")
+			fmt.Fprintf(t.Writer, ", ")
 		}
 	}
-	fmt.Println(")")
+	fmt.Fprintln(t.Writer, ")")
 }
 
-// visualizeLogicFunction conditionally prints a formal representation of a
-// logic function to stdout.
-func visualizeLogicFunction(minterms []uint64, dontCares []uint64, show bool) {
-	if show {
-		fmt.Printf("FUNCTION_ADDED: S")
-		visualizeLogicFunctionList(minterms)
-		if len(dontCares) > 0 {
-			fmt.Printf("                D")
-			visualizeLogicFunctionList(dontCares)
-		}
-		fmt.Printf("\n")
+// visualizeLogicFunction prints a formal representation of a logic function,
+// with its off-set if it is given.
+func (t TextObserver) visualizeLogicFunction(minterms []string, dontCares []string, offSet []string) {
+	fmt.Fprintf(t.Writer, "FUNCTION_ADDED: S")
+	t.visualizeLogicFunctionList(minterms)
+	if len(dontCares) > 0 {
+		fmt.Fprintf(t.Writer, "                D")
+		t.visualizeLogicFunctionList(dontCares)
 	}
+	if offSet != nil {
+		fmt.Fprintf(t.Writer, "                R")
+		t.visualizeLogicFunctionList(offSet)
+	}
+	fmt.Fprintf(t.Writer, "\n")
 }
 
-// visualizeImplicantTableHorizontalBar prints a horizontal bar to stdout with
-// the correct dimentions for the calling implicant table.
-func visualizeImplicantTableHorizontalBar(columns int, headingWidth1 int, headingWidth2 int) {
+// visualizeImplicantTableHorizontalBar prints a horizontal bar with the
+// correct dimentions for an implicant table.
+func (t TextObserver) visualizeImplicantTableHorizontalBar(columns int, headingWidth1 int, headingWidth2 int) {
 	for i := 0; i < columns; i++ {
-		fmt.Printf("+%s%s-------------",
+		fmt.Fprintf(t.Writer, "+%s%s-------------",
 			strings.Repeat("-", int(math.Max(4, float64(headingWidth1)))),
 			strings.Repeat("-", int(math.Max(4, float64(headingWidth2)))))
 	}
-	fmt.Println("+")
+	fmt.Fprintln(t.Writer, "+")
 }
 
-// visualizeImplicantTableHeader prints a table header to stdout with the
-// correct dimentions for the calling implicant table.
-func visualizeImplicantTableHeader(columns int, headingWidth1 int, headingWidth2 int) {
-	visualizeImplicantTableHorizontalBar(columns, headingWidth1, headingWidth2)
+// visualizeImplicantTableHeader prints a table header with the correct
+// dimentions for an implicant table.
+func (t TextObserver) visualizeImplicantTableHeader(columns int, headingWidth1 int, headingWidth2 int) {
+	t.visualizeImplicantTableHorizontalBar(columns, headingWidth1, headingWidth2)
 	for i := 0; i < columns; i++ {
-		fmt.Printf("| term  %stags  %schecked ",
+		fmt.Fprintf(t.Writer, "| term  %stags  %schecked ",
 			strings.Repeat(" ", int(math.Max(0, float64(headingWidth1-4)))),
 			strings.Repeat(" ", int(math.Max(0, float64(headingWidth2-4)))))
 	}
-	fmt.Println("|")
-	visualizeImplicantTableHorizontalBar(columns, headingWidth1, headingWidth2)
+	fmt.Fprintln(t.Writer, "|")
+	t.visualizeImplicantTableHorizontalBar(columns, headingWidth1, headingWidth2)
 }
 
-// visualizeImplicantTableNilEntry prints an empty column to stdout with the
-// correct dimentions for the calling implicant table.
-func visualizeImplicantTableNilEntry(headingWidth1 int, headingWidth2 int) {
-	fmt.Printf("| %s  %s          ",
+// visualizeImplicantTableNilEntry prints an empty column with the correct
+// dimentions for an implicant table.
+func (t TextObserver) visualizeImplicantTableNilEntry(headingWidth1 int, headingWidth2 int) {
+	fmt.Fprintf(t.Writer, "| %s  %s          ",
 		strings.Repeat(" ", int(math.Max(4, float64(headingWidth1)))),
 		strings.Repeat(" ", int(math.Max(4, float64(headingWidth2)))))
 }
 
-// ImplicantTable.visualize conditionally prints a tabular representation of the
-// calling implicant table to stdout.
-func (table implicantTable) visualize(implicantDisplayWidth int, show bool) {
-	if show {
-		visualizeImplicantTableHeader(len(table.columns), implicantDisplayWidth, table.nOutputs)
-		for group := 0; group < len(table.columns[0]); group++ {
-			groupEntries := [][]implicant{}
-			nGroupEntries := 0
-			for k, column := range table.columns {
-				groupEntries = append(groupEntries, []implicant{})
-				if group < len(column) {
-					for entry := range column[group] {
-						groupEntries[k] = append(groupEntries[k], entry)
-					}
-					sort.Slice(groupEntries[k], func(i, j int) bool {
-						return (groupEntries[k][i].literals < groupEntries[k][j].literals) || (bitCount(groupEntries[k][i].xMask) < bitCount(groupEntries[k][j].xMask))
-					})
-					nGroupEntries = int(math.Max(float64(nGroupEntries), float64(len(column[group]))))
-				}
+// visualizeImplicantTable prints a tabular representation of the passed
+// columns of an implicant table.
+func (t TextObserver) visualizeImplicantTable(columns [][][]TableEntry, nOutputs int) {
+	implicantDisplayWidth := 0
+	for _, group := range columns[0] {
+		if len(group) > 0 {
+			implicantDisplayWidth = group[0].Implicant.width
+			break
+		}
+	}
+
+	t.visualizeImplicantTableHeader(len(columns), implicantDisplayWidth, nOutputs)
+	for group := 0; group < len(columns[0]); group++ {
+		nGroupEntries := 0
+		for _, column := range columns {
+			if group < len(column) {
+				nGroupEntries = int(math.Max(float64(nGroupEntries), float64(len(column[group]))))
 			}
-			for entry := 0; entry < nGroupEntries; entry++ {
-				for i, column := range groupEntries {
-					if group < len(table.columns[i]) {
-						if entry < len(column) {
-							f := "| %s%s  %0" + strconv.FormatInt(int64(table.nOutputs), 10) + "b%s  %t   "
-							fmt.Printf(f,
-								column[entry].stringify(implicantDisplayWidth),
-								strings.Repeat(" ", int(math.Max(0, float64(4-implicantDisplayWidth)))),
-								column[entry].tag,
-								strings.Repeat(" ", int(math.Max(0, float64(4-table.nOutputs)))),
-								column[entry].checked)
-							if column[entry].checked {
-								fmt.Printf(" ")
-							}
-						} else {
-							visualizeImplicantTableNilEntry(implicantDisplayWidth, table.nOutputs)
-						}
-					} else {
-						visualizeImplicantTableNilEntry(implicantDisplayWidth, table.nOutputs)
+		}
+		for entry := 0; entry < nGroupEntries; entry++ {
+			for _, column := range columns {
+				if group < len(column) && entry < len(column[group]) {
+					e := column[group][entry]
+					f := "| %s%s  %s%s  %t   "
+					fmt.Fprintf(t.Writer, f,
+						e.Implicant.String(),
+						strings.Repeat(" ", int(math.Max(0, float64(4-implicantDisplayWidth)))),
+						e.Implicant.im.tag.binary(nOutputs),
+						strings.Repeat(" ", int(math.Max(0, float64(4-nOutputs)))),
+						e.Checked)
+					if e.Checked {
+						fmt.Fprintf(t.Writer, " ")
 					}
+				} else {
+					t.visualizeImplicantTableNilEntry(implicantDisplayWidth, nOutputs)
 				}
-				fmt.Printf("|\n")
 			}
-			visualizeImplicantTableHorizontalBar(len(table.columns), implicantDisplayWidth, table.nOutputs)
+			fmt.Fprintf(t.Writer, "|\n")
 		}
-		fmt.Printf("\n")
+		t.visualizeImplicantTableHorizontalBar(len(columns), implicantDisplayWidth, nOutputs)
 	}
+	fmt.Fprintf(t.Writer, "\n")
 }
 
-// visualizePrimeImplicantList conditionally prints a list of implicants to
-// stdout.
-func visualizePrimeImplicantList(primes []implicant, implicantDisplayWidth int, show bool) {
-	if show {
-		visualizeHeading("PRIME IMPLICANTS:", true)
-		for _, prime := range primes {
-			fmt.Println("  " + prime.stringify(implicantDisplayWidth))
-		}
-		fmt.Printf("\n")
+// visualizePrimeImplicantList prints a list of prime implicants.
+func (t TextObserver) visualizePrimeImplicantList(primes []Implicant) {
+	t.visualizeHeading("PRIME IMPLICANTS:")
+	for _, prime := range primes {
+		fmt.Fprintln(t.Writer, "  "+prime.String())
 	}
+	fmt.Fprintf(t.Writer, "\n")
 }
 
 // ----------------------------------------------------------------
 //   COVER TABLE VISUALIZATION
 // ----------------------------------------------------------------
 
-// visualizeCoverTableHorizontalBar prints a horizontal bar to stdout with the
-// correct dimentions for the calling cover table.
-func visualizeCoverTableHorizontalBar(columns int, implicantDisplayWidth int, mintermDisplayWidth int) {
-	fmt.Printf("+-%s-+-", strings.Repeat("-", implicantDisplayWidth))
-	fmt.Printf("%s+\n", strings.Repeat("-", mintermDisplayWidth*columns))
+// visualizeCoverTableHorizontalBar prints a horizontal bar with the correct
+// dimentions for a cover table.
+func (t TextObserver) visualizeCoverTableHorizontalBar(columns int, implicantDisplayWidth int, mintermDisplayWidth int) {
+	fmt.Fprintf(t.Writer, "+-%s-+-", strings.Repeat("-", implicantDisplayWidth))
+	fmt.Fprintf(t.Writer, "%s+\n", strings.Repeat("-", mintermDisplayWidth*columns))
 }
 
-// visualizeCoverTableHeader prints a table header to stdout with the correct
-// dimentions for the calling cover table.
-func visualizeCoverTableHeader(minterms [][]uint64, columns int, implicantDisplayWidth int, mintermDisplayWidth int) {
-	visualizeCoverTableHorizontalBar(columns, implicantDisplayWidth, mintermDisplayWidth)
-	fmt.Printf("| %s | ", strings.Repeat(" ", implicantDisplayWidth))
-	for _, output := range minterms {
-		for _, minterm := range output {
-			s := fmt.Sprintf("%d", minterm)
-			fmt.Printf("%s%s", s, strings.Repeat(" ", mintermDisplayWidth-len(s)))
-		}
+// visualizeCoverTableHeader prints a table header with the correct dimentions
+// for a cover table.
+func (t TextObserver) visualizeCoverTableHeader(minterms []string, implicantDisplayWidth int, mintermDisplayWidth int) {
+	t.visualizeCoverTableHorizontalBar(len(minterms), implicantDisplayWidth, mintermDisplayWidth)
+	fmt.Fprintf(t.Writer, "| %s | ", strings.Repeat(" ", implicantDisplayWidth))
+	for _, s := range minterms {
+		fmt.Fprintf(t.Writer, "%s%s", s, strings.Repeat(" ", mintermDisplayWidth-len(s)))
 	}
-	fmt.Println("|")
-	visualizeCoverTableHorizontalBar(columns, implicantDisplayWidth, mintermDisplayWidth)
+	fmt.Fprintln(t.Writer, "|")
+	t.visualizeCoverTableHorizontalBar(len(minterms), implicantDisplayWidth, mintermDisplayWidth)
 }
 
-// ImplicantTable.visualize conditionally prints a tabular representation of
-// the calling cover table to stdout.
-func (table coverTable) visualize(implicantDisplayWidth int, mintermDisplayWidth int, show bool) {
-	if show {
-		totalMinterms := 0
-		for _, output := range table.remainingMinterms {
-			totalMinterms += len(output)
+// visualizeCoverTable prints a tabular representation of a cover table, with
+// a row for each prime and a column for each minterm remaining to be covered.
+func (t TextObserver) visualizeCoverTable(table CoverProblem) {
+	implicantDisplayWidth := 0
+	minterms := []string{}
+	mintermDisplayWidth := 2
+	for _, row := range table.Rows {
+		s := decimalTerm(row.Minterm)
+		minterms = append(minterms, s)
+		if len(s)+1 > mintermDisplayWidth {
+			mintermDisplayWidth = len(s) + 1
 		}
+	}
+	if len(table.Primes) > 0 {
+		implicantDisplayWidth = table.Primes[0].width
+	}
 
-		visualizeCoverTableHeader(table.remainingMinterms, totalMinterms, implicantDisplayWidth, mintermDisplayWidth)
-		// print prime implicant covers
-		for i, prime := range table.primes {
-			fmt.Printf("| %s | ", prime.stringify(implicantDisplayWidth))
-			for j, output := range table.remainingMinterms {
-				for _, minterm := range output {
-					if slices.Contains(table.covers[i][j], minterm) {
-						fmt.Printf("x %s", strings.Repeat(" ", mintermDisplayWidth-2))
-					} else {
-						fmt.Printf("%s", strings.Repeat(" ", mintermDisplayWidth))
-					}
-				}
+	t.visualizeCoverTableHeader(minterms, implicantDisplayWidth, mintermDisplayWidth)
+	// print prime implicant covers
+	for p, prime := range table.Primes {
+		fmt.Fprintf(t.Writer, "| %s | ", prime.String())
+		for r := range table.Rows {
+			if slices.Contains(table.Covers[p], r) {
+				fmt.Fprintf(t.Writer, "x %s", strings.Repeat(" ", mintermDisplayWidth-2))
+			} else {
+				fmt.Fprintf(t.Writer, "%s", strings.Repeat(" ", mintermDisplayWidth))
 			}
-			fmt.Println("|")
 		}
-		visualizeCoverTableHorizontalBar(totalMinterms, implicantDisplayWidth, mintermDisplayWidth)
-		fmt.Printf("\n")
+		fmt.Fprintln(t.Writer, "|")
+	}
+	t.visualizeCoverTableHorizontalBar(len(table.Rows), implicantDisplayWidth, mintermDisplayWidth)
+	fmt.Fprintf(t.Writer, "\n")
+}
+
+// ----------------------------------------------------------------
+//   COVER VISUALIZATION
+// ----------------------------------------------------------------
+
+// visualizeCover prints a heading followed by a list of cubes and the outputs
+// they apply to.
+func (t TextObserver) visualizeCover(heading string, cover []Implicant, nOutputs int) {
+	t.visualizeHeading(heading)
+	for _, c := range cover {
+		fmt.Fprintf(t.Writer, "  %s  %s\n", c.String(), c.im.tag.binary(nOutputs))
 	}
+	fmt.Fprintf(t.Writer, "\n")
 }